@@ -0,0 +1,363 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+// Package arcflash provides arc flash incident energy calculations per IEEE 1584-2018, using bolted fault currents
+// and protective device clearing times from Oneliner.
+package arcflash
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Electrode represents an IEEE 1584-2018 electrode configuration.
+type Electrode int
+
+// Electrode configurations.
+const (
+	VCB  Electrode = iota // Vertical conductors in a box.
+	VCBB                  // Vertical conductors terminated in an insulating barrier in a box.
+	HCB                   // Horizontal conductors in a box.
+	VOA                   // Vertical conductors in open air.
+	HOA                   // Horizontal conductors in open air.
+)
+
+var electrodeNames = [...]string{"VCB", "VCBB", "HCB", "VOA", "HOA"}
+
+// String implements the stringer interface for the Electrode type.
+func (e Electrode) String() string {
+	if e < 0 || int(e) >= len(electrodeNames) {
+		return fmt.Sprintf("Electrode(%d)", int(e))
+	}
+	return electrodeNames[e]
+}
+
+// ParseElectrode parses an electrode configuration name, case insensitive.
+func ParseElectrode(s string) (Electrode, error) {
+	for i, name := range electrodeNames {
+		if strings.EqualFold(strings.TrimSpace(s), name) {
+			return Electrode(i), nil
+		}
+	}
+	return 0, fmt.Errorf("ParseElectrode: unknown electrode configuration %q", s)
+}
+
+// open reports whether the electrode configuration is in open air.
+func (e Electrode) open() bool {
+	return e == VOA || e == HOA
+}
+
+// JoulesPerCal converts incident energy from J/cm² to cal/cm².
+const JoulesPerCal = 4.184
+
+// Table 1, intermediate arcing current coefficients k1 to k10 for 600, 2700 and 14300 V.
+var arcingCoeffs = [5][3][10]float64{
+	VCB: {
+		{-0.04287, 1.035, -0.083, 0, 0, -4.783e-09, 1.962e-06, -0.000229, 0.003141, 1.092},
+		{0.0065, 1.001, -0.024, -1.557e-12, 4.556e-10, -4.186e-08, 8.346e-07, 5.482e-05, -0.003191, 0.9729},
+		{0.005795, 1.015, -0.011, -1.557e-12, 4.556e-10, -4.186e-08, 8.346e-07, 5.482e-05, -0.003191, 0.9729},
+	},
+	VCBB: {
+		{-0.017432, 0.98, -0.05, 0, 0, -5.767e-09, 2.524e-06, -0.00034, 0.01187, 1.013},
+		{0.002823, 0.995, -0.0125, 0, -9.204e-11, 2.901e-08, -3.262e-06, 0.0001569, -0.004003, 0.9825},
+		{0.014827, 1.01, -0.01, 0, -9.204e-11, 2.901e-08, -3.262e-06, 0.0001569, -0.004003, 0.9825},
+	},
+	HCB: {
+		{0.054922, 0.988, -0.11, 0, 0, -5.382e-09, 2.316e-06, -0.000302, 0.0091, 0.9725},
+		{0.001011, 1.003, -0.0249, 0, 0, 4.859e-10, -1.814e-07, -9.128e-06, -0.0007, 0.9881},
+		{0.008693, 0.999, -0.02, 0, -5.043e-11, 2.233e-08, -3.046e-06, 0.000116, -0.001145, 0.9839},
+	},
+	VOA: {
+		{0.043785, 1.04, -0.18, 0, 0, -4.783e-09, 1.962e-06, -0.000229, 0.003141, 1.092},
+		{-0.02395, 1.006, -0.0188, -1.557e-12, 4.556e-10, -4.186e-08, 8.346e-07, 5.482e-05, -0.003191, 0.9729},
+		{0.005371, 1.0102, -0.029, -1.557e-12, 4.556e-10, -4.186e-08, 8.346e-07, 5.482e-05, -0.003191, 0.9729},
+	},
+	HOA: {
+		{0.111147, 1.008, -0.24, 0, 0, -3.895e-09, 1.641e-06, -0.000197, 0.002615, 1.1},
+		{0.000435, 1.006, -0.038, 0, 0, 7.859e-10, -1.914e-07, -9.128e-06, -0.0007, 0.9981},
+		{0.000904, 0.999, -0.02, 0, 0, 7.859e-10, -1.914e-07, -9.128e-06, -0.0007, 0.9981},
+	},
+}
+
+// Table 2, arcing current variation correction factor coefficients k1 to k7.
+var variationCoeffs = [5][7]float64{
+	VCB:  {0, -1.4269e-06, 8.3137e-05, -0.0019382, 0.022366, -0.12645, 0.30226},
+	VCBB: {1.138e-06, -6.0287e-05, 0.0012758, -0.013778, 0.080217, -0.24066, 0.33524},
+	HCB:  {0, -3.097e-06, 0.00016405, -0.0033609, 0.033308, -0.16182, 0.34627},
+	VOA:  {9.5606e-07, -5.1543e-05, 0.0011161, -0.01242, 0.075125, -0.23584, 0.33696},
+	HOA:  {0, -3.1555e-06, 0.0001682, -0.0034607, 0.034124, -0.1599, 0.34629},
+}
+
+// Tables 3, 4 and 5, intermediate incident energy coefficients k1 to k13 for 600, 2700 and 14300 V.
+var energyCoeffs = [5][3][13]float64{
+	VCB: {
+		{0.753364, 0.566, 1.752636, 0, 0, -4.783e-09, 1.962e-06, -0.000229, 0.003141, 1.092, 0, -1.598, 0.957},
+		{2.40021, 0.165, 0.354202, -1.557e-12, 4.556e-10, -4.186e-08, 8.346e-07, 5.482e-05, -0.003191, 0.9729, 0, -1.569, 0.9778},
+		{3.825917, 0.11, -0.999749, -1.557e-12, 4.556e-10, -4.186e-08, 8.346e-07, 5.482e-05, -0.003191, 0.9729, 0, -1.568, 0.99},
+	},
+	VCBB: {
+		{3.068459, 0.26, -0.098107, 0, 0, -5.767e-09, 2.524e-06, -0.00034, 0.01187, 1.013, -0.06, -1.809, 1.19},
+		{3.870592, 0.185, -0.736618, 0, -9.204e-11, 2.901e-08, -3.262e-06, 0.0001569, -0.004003, 0.9825, 0, -1.742, 1.09},
+		{3.644309, 0.215, -0.585522, 0, -9.204e-11, 2.901e-08, -3.262e-06, 0.0001569, -0.004003, 0.9825, 0, -1.677, 1.06},
+	},
+	HCB: {
+		{4.073745, 0.344, -0.370259, 0, 0, -5.382e-09, 2.316e-06, -0.000302, 0.0091, 0.9725, 0, -2.03, 1.036},
+		{3.486391, 0.177, -0.193101, 0, 0, 4.859e-10, -1.814e-07, -9.128e-06, -0.0007, 0.9881, 0.027, -1.723, 1.055},
+		{3.044516, 0.125, 0.245106, 0, -5.043e-11, 2.233e-08, -3.046e-06, 0.000116, -0.001145, 0.9839, 0, -1.655, 1.084},
+	},
+	VOA: {
+		{0.679294, 0.746, 1.222636, 0, 0, -4.783e-09, 1.962e-06, -0.000229, 0.003141, 1.092, 0, -1.598, 0.997},
+		{3.880724, 0.105, -1.906033, -1.557e-12, 4.556e-10, -4.186e-08, 8.346e-07, 5.482e-05, -0.003191, 0.9729, 0, -1.515, 1.115},
+		{3.405454, 0.12, -0.93245, -1.557e-12, 4.556e-10, -4.186e-08, 8.346e-07, 5.482e-05, -0.003191, 0.9729, 0, -1.534, 0.979},
+	},
+	HOA: {
+		{3.470417, 0.465, -0.261863, 0, 0, -3.895e-09, 1.641e-06, -0.000197, 0.002615, 1.1, 0, -1.99, 1.04},
+		{3.616266, 0.149, -0.761561, 0, 0, 7.859e-10, -1.914e-07, -9.128e-06, -0.0007, 0.9981, 0, -1.639, 1.078},
+		{2.04049, 0.177, 1.005092, 0, 0, 7.859e-10, -1.914e-07, -9.128e-06, -0.0007, 0.9981, -0.05, -1.633, 1.151},
+	},
+}
+
+// Table 7, equivalent enclosure size coefficients A and B.
+var enclosureCoeffs = [3][2]float64{
+	VCB:  {4, 20},
+	VCBB: {10, 24},
+	HCB:  {10, 22},
+}
+
+// Table 8, enclosure size correction factor coefficients b1 to b3 for typical and shallow enclosures.
+var correctionCoeffs = [2][3][3]float64{
+	{ // Typical.
+		VCB:  {-0.000302, 0.03441, 0.4325},
+		VCBB: {-0.0002976, 0.032, 0.479},
+		HCB:  {-0.0001923, 0.01935, 0.6899},
+	},
+	{ // Shallow.
+		VCB:  {0.002222, -0.02556, 0.6222},
+		VCBB: {-0.002778, 0.1194, -0.2778},
+		HCB:  {-0.0005556, 0.03722, 0.4778},
+	},
+}
+
+// Params represents the IEEE 1584-2018 model inputs for a piece of equipment.
+type Params struct {
+	Electrode Electrode
+	Voc       float64 // Open circuit line to line voltage in kV.
+	Ibf       float64 // Bolted three phase fault current in kA.
+	Gap       float64 // Conductor gap in mm.
+	Distance  float64 // Working distance in mm.
+
+	// Enclosure dimensions in mm, not used for open air configurations.
+	Height, Width, Depth float64
+}
+
+// Validate returns an error if the parameters are outside the range of the model.
+func (p Params) Validate() error {
+	switch {
+	case p.Electrode < VCB || p.Electrode > HOA:
+		return fmt.Errorf("Validate: unknown electrode configuration %v", p.Electrode)
+	case p.Voc < 0.208 || p.Voc > 15:
+		return fmt.Errorf("Validate: voltage %g kV outside 0.208 to 15 kV", p.Voc)
+	case p.Voc <= 0.6 && (p.Ibf < 0.5 || p.Ibf > 106):
+		return fmt.Errorf("Validate: bolted fault current %g kA outside 0.5 to 106 kA", p.Ibf)
+	case p.Voc > 0.6 && (p.Ibf < 0.2 || p.Ibf > 65):
+		return fmt.Errorf("Validate: bolted fault current %g kA outside 0.2 to 65 kA", p.Ibf)
+	case p.Voc <= 0.6 && (p.Gap < 6.35 || p.Gap > 76.2):
+		return fmt.Errorf("Validate: gap %g mm outside 6.35 to 76.2 mm", p.Gap)
+	case p.Voc > 0.6 && (p.Gap < 19.05 || p.Gap > 254):
+		return fmt.Errorf("Validate: gap %g mm outside 19.05 to 254 mm", p.Gap)
+	case p.Distance < 305:
+		return fmt.Errorf("Validate: working distance %g mm less than 305 mm", p.Distance)
+	case !p.Electrode.open() && (p.Height <= 0 || p.Width <= 0 || p.Depth <= 0):
+		return fmt.Errorf("Validate: enclosure dimensions required for %v", p.Electrode)
+	}
+	return nil
+}
+
+// poly evaluates the polynomial with the coefficients in descending order of power.
+func poly(x float64, k ...float64) float64 {
+	var y float64
+	for _, ki := range k {
+		y = y*x + ki
+	}
+	return y
+}
+
+// intermediateArcing returns the intermediate arcing currents at 600, 2700 and 14300 V in kA, equation 1.
+func (p Params) intermediateArcing() [3]float64 {
+	var iarc [3]float64
+	for i, k := range arcingCoeffs[p.Electrode] {
+		iarc[i] = math.Pow(10, k[0]+k[1]*math.Log10(p.Ibf)+k[2]*math.Log10(p.Gap)) *
+			poly(p.Ibf, k[3], k[4], k[5], k[6], k[7], k[8], k[9])
+	}
+	return iarc
+}
+
+// interpolate interpolates the intermediate values at 600, 2700 and 14300 V to the open circuit voltage above
+// 600 V, equations 16 to 24.
+func interpolate(voc float64, x [3]float64) float64 {
+	x1 := (x[1]-x[0])/2.1*(voc-2.7) + x[1]
+	x2 := (x[2]-x[1])/11.6*(voc-14.3) + x[2]
+	if voc > 2.7 {
+		return x2
+	}
+	return x1*(2.7-voc)/2.1 + x2*(voc-0.6)/2.1
+}
+
+// finalArcing returns the final arcing current from the intermediate arcing currents in kA, equations 16 to 25.
+func (p Params) finalArcing(iarc [3]float64) float64 {
+	if p.Voc > 0.6 {
+		return interpolate(p.Voc, iarc)
+	}
+	r := 0.6 / p.Voc
+	return 1 / math.Sqrt(r*r*(1/(iarc[0]*iarc[0])-(0.36-p.Voc*p.Voc)/(0.36*p.Ibf*p.Ibf)))
+}
+
+// VariationFactor returns the arcing current variation correction factor, equation 2.
+func (p Params) VariationFactor() float64 {
+	k := variationCoeffs[p.Electrode]
+	return poly(p.Voc, k[:]...)
+}
+
+// ArcingCurrent returns the final arcing current and the reduced arcing current, in kA.
+func (p Params) ArcingCurrent() (iarc, iarcMin float64) {
+	iarc = p.finalArcing(p.intermediateArcing())
+	return iarc, iarc * (1 - 0.5*p.VariationFactor())
+}
+
+// CorrectionFactor returns the enclosure size correction factor, equations 10 to 14. Open air configurations
+// return 1.
+func (p Params) CorrectionFactor() float64 {
+	if p.Electrode.open() {
+		return 1
+	}
+	shallow := p.Voc < 0.6 && p.Height < 508 && p.Width < 508 && p.Depth <= 203.2
+	a, b := enclosureCoeffs[p.Electrode][0], enclosureCoeffs[p.Electrode][1]
+
+	// Equivalent dimensions in inches, Table 6.
+	equivalent := func(dim float64, direct bool) float64 {
+		switch {
+		case dim < 508:
+			if shallow {
+				return 0.03937 * dim
+			}
+			return 20
+		case dim <= 660.4 || direct:
+			return 0.03937 * math.Min(dim, 1244.6)
+		}
+		return (660.4 + (math.Min(dim, 1244.6)-660.4)*((p.Voc+a)/b)) / 25.4
+	}
+	width := equivalent(p.Width, false)
+	height := equivalent(p.Height, p.Electrode == VCB)
+	ees := (height + width) / 2
+
+	k := correctionCoeffs[0][p.Electrode]
+	if shallow {
+		k = correctionCoeffs[1][p.Electrode]
+		return 1 / poly(ees, k[:]...)
+	}
+	return poly(ees, k[:]...)
+}
+
+// energyExponent returns the exponent of the intermediate incident energy equations 3 to 6, excluding the
+// working distance term.
+func (p Params) energyExponent(k [13]float64, iarcPoly, iarc, cf float64) float64 {
+	return k[0] + k[1]*math.Log10(p.Gap) +
+		k[2]*iarcPoly/poly(p.Ibf, k[3], k[4], k[5], k[6], k[7], k[8], k[9], 0) +
+		k[10]*math.Log10(p.Ibf) + k[12]*math.Log10(iarc) + math.Log10(1/cf)
+}
+
+// energyAndBoundary returns the incident energy in J/cm² and arc flash boundary in mm for the intermediate arcing
+// currents and arcing duration in ms.
+func (p Params) energyAndBoundary(iarcs [3]float64, iarc, t float64) (e, afb float64) {
+	cf := p.CorrectionFactor()
+	ks := energyCoeffs[p.Electrode]
+	energy := func(k [13]float64, x float64) float64 {
+		return 12.552 / 50 * t * math.Pow(10, x+k[11]*math.Log10(p.Distance))
+	}
+	boundary := func(k [13]float64, x float64) float64 {
+		// Equations 7 to 9, 20/T approximates the 5.0 J/cm² (1.2 cal/cm²) boundary energy.
+		return math.Pow(10, (math.Log10(20/t)-x)/k[11])
+	}
+	if p.Voc <= 0.6 {
+		x := p.energyExponent(ks[0], iarcs[0], iarc, cf)
+		return energy(ks[0], x), boundary(ks[0], x)
+	}
+	var es, afbs [3]float64
+	for i, k := range ks {
+		x := p.energyExponent(k, iarcs[i], iarcs[i], cf)
+		es[i], afbs[i] = energy(k, x), boundary(k, x)
+	}
+	return interpolate(p.Voc, es), interpolate(p.Voc, afbs)
+}
+
+// Result represents the arc flash results for a single arcing current.
+type Result struct {
+	Iarc     float64 // Arcing current in kA.
+	Time     float64 // Arc duration in ms.
+	Energy   float64 // Incident energy at the working distance in J/cm².
+	Boundary float64 // Arc flash boundary in mm.
+}
+
+// EnergyCal returns the incident energy in cal/cm².
+func (r Result) EnergyCal() float64 {
+	return r.Energy / JoulesPerCal
+}
+
+// Evaluation represents the arc flash results at the final and reduced arcing currents.
+type Evaluation struct {
+	Params  Params
+	Nominal Result
+	Reduced Result
+}
+
+// Worst returns the result with the highest incident energy.
+func (e Evaluation) Worst() Result {
+	if e.Reduced.Energy > e.Nominal.Energy {
+		return e.Reduced
+	}
+	return e.Nominal
+}
+
+// ClearingTime returns the arc duration in ms for the provided arcing current in kA.
+type ClearingTime func(iarc float64) (float64, error)
+
+// FixedTime returns a ClearingTime with a constant arc duration in ms.
+func FixedTime(ms float64) ClearingTime {
+	return func(float64) (float64, error) {
+		return ms, nil
+	}
+}
+
+// Evaluate calculates the incident energy and arc flash boundary at both the final and reduced arcing currents,
+// each with the arc duration returned by the clearing time function at that arcing current.
+func Evaluate(p Params, clearing ClearingTime) (Evaluation, error) {
+	if err := p.Validate(); err != nil {
+		return Evaluation{}, fmt.Errorf("Evaluate: %v", err)
+	}
+	ev := Evaluation{Params: p}
+	iarcs := p.intermediateArcing()
+	factor := 1 - 0.5*p.VariationFactor()
+	var iarcsMin [3]float64
+	for i := range iarcs {
+		iarcsMin[i] = iarcs[i] * factor
+	}
+	for _, c := range []struct {
+		iarcs [3]float64
+		r     *Result
+	}{{iarcs, &ev.Nominal}, {iarcsMin, &ev.Reduced}} {
+		iarc := p.finalArcing(c.iarcs)
+		if p.Voc <= 0.6 && c.r == &ev.Reduced {
+			// Low voltage reduced arcing current is applied to the final arcing current.
+			iarc = p.finalArcing(iarcs) * factor
+		}
+		t, err := clearing(iarc)
+		if err != nil {
+			return Evaluation{}, fmt.Errorf("Evaluate: %v", err)
+		}
+		e, afb := p.energyAndBoundary(c.iarcs, iarc, t)
+		*c.r = Result{Iarc: iarc, Time: t, Energy: e, Boundary: afb}
+	}
+	return ev, nil
+}
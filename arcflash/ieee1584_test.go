@@ -0,0 +1,144 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package arcflash
+
+import (
+	"math"
+	"testing"
+)
+
+// within reports whether got is within the relative tolerance of expected.
+func within(got, expected, tol float64) bool {
+	return math.Abs(got-expected) <= tol*math.Abs(expected)
+}
+
+// IEEE 1584-2018 Annex D worked examples.
+func TestEvaluate_AnnexD(t *testing.T) {
+	tests := []struct {
+		name          string
+		p             Params
+		t             float64
+		iarc600, iarc float64
+		cf            float64
+		energy, afb   float64
+	}{
+		{
+			name:    "D.1 medium voltage",
+			p:       Params{Electrode: VCB, Voc: 4.16, Ibf: 15, Gap: 104, Distance: 914.4, Height: 1143, Width: 762, Depth: 762},
+			t:       197,
+			iarc600: 11.117, iarc: 12.979,
+			cf:     1.284,
+			energy: 12.152,
+		},
+		{
+			name:    "D.2 low voltage",
+			p:       Params{Electrode: VCB, Voc: 0.48, Ibf: 45, Gap: 32, Distance: 609.6, Height: 610, Width: 610, Depth: 254},
+			t:       61.3,
+			iarc600: 32.449, iarc: 28.793,
+			cf:     1.085,
+			energy: 11.585, afb: 1029,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.p.intermediateArcing()[0]; !within(got, tt.iarc600, 1e-4) {
+				t.Errorf("Iarc_600: expected %v, got %v", tt.iarc600, got)
+			}
+			if got := tt.p.CorrectionFactor(); !within(got, tt.cf, 1e-3) {
+				t.Errorf("CF: expected %v, got %v", tt.cf, got)
+			}
+			ev, err := Evaluate(tt.p, FixedTime(tt.t))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !within(ev.Nominal.Iarc, tt.iarc, 1e-4) {
+				t.Errorf("Iarc: expected %v, got %v", tt.iarc, ev.Nominal.Iarc)
+			}
+			if !within(ev.Nominal.Energy, tt.energy, 1e-3) {
+				t.Errorf("E: expected %v, got %v", tt.energy, ev.Nominal.Energy)
+			}
+			if tt.afb != 0 && !within(ev.Nominal.Boundary, tt.afb, 2e-3) {
+				t.Errorf("AFB: expected %v, got %v", tt.afb, ev.Nominal.Boundary)
+			}
+			if ev.Reduced.Iarc >= ev.Nominal.Iarc {
+				t.Errorf("expected reduced arcing current below %v, got %v", ev.Nominal.Iarc, ev.Reduced.Iarc)
+			}
+		})
+	}
+}
+
+func TestEvaluate_ClearingTime(t *testing.T) {
+	p := Params{Electrode: VCB, Voc: 0.48, Ibf: 45, Gap: 32, Distance: 609.6, Height: 610, Width: 610, Depth: 254}
+
+	// An inverse time device operating slower at the reduced arcing current.
+	ev, err := Evaluate(p, func(iarc float64) (float64, error) {
+		return 2000 / iarc, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev.Reduced.Time <= ev.Nominal.Time {
+		t.Errorf("expected longer duration at reduced arcing current, got %v and %v", ev.Reduced.Time, ev.Nominal.Time)
+	}
+	if w := ev.Worst(); w.Energy < ev.Nominal.Energy || w.Energy < ev.Reduced.Energy {
+		t.Errorf("expected worst case energy, got %v", w)
+	}
+
+	// Incident energy is proportional to arc duration.
+	e1, _ := Evaluate(p, FixedTime(100))
+	e2, _ := Evaluate(p, FixedTime(200))
+	if !within(e2.Nominal.Energy, 2*e1.Nominal.Energy, 1e-9) {
+		t.Errorf("expected energy to double, got %v and %v", e1.Nominal.Energy, e2.Nominal.Energy)
+	}
+}
+
+func TestEvaluate_Interpolation(t *testing.T) {
+	// Results are continuous across the 2.7 kV intermediate voltage.
+	p := Params{Electrode: HCB, Ibf: 20, Gap: 50, Distance: 914.4, Height: 1143, Width: 762, Depth: 762}
+	var last float64
+	for _, voc := range []float64{2.69, 2.7, 2.71} {
+		p.Voc = voc
+		ev, err := Evaluate(p, FixedTime(100))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if last != 0 && !within(ev.Nominal.Energy, last, 0.01) {
+			t.Errorf("%v kV: discontinuous energy %v from %v", voc, ev.Nominal.Energy, last)
+		}
+		last = ev.Nominal.Energy
+	}
+}
+
+func TestParams_Validate(t *testing.T) {
+	valid := Params{Electrode: VOA, Voc: 13.8, Ibf: 20, Gap: 152, Distance: 914.4}
+	if err := valid.Validate(); err != nil {
+		t.Error(err)
+	}
+	invalid := []Params{
+		{Electrode: VOA, Voc: 20, Ibf: 20, Gap: 152, Distance: 914.4},
+		{Electrode: VOA, Voc: 13.8, Ibf: 80, Gap: 152, Distance: 914.4},
+		{Electrode: VOA, Voc: 0.48, Ibf: 20, Gap: 152, Distance: 914.4},
+		{Electrode: VOA, Voc: 13.8, Ibf: 20, Gap: 152, Distance: 100},
+		{Electrode: VCB, Voc: 13.8, Ibf: 20, Gap: 152, Distance: 914.4},
+		{Electrode: Electrode(7), Voc: 13.8, Ibf: 20, Gap: 152, Distance: 914.4},
+	}
+	for _, p := range invalid {
+		if err := p.Validate(); err == nil {
+			t.Errorf("expected error for %+v", p)
+		}
+	}
+}
+
+func TestParseElectrode(t *testing.T) {
+	for i, name := range electrodeNames {
+		e, err := ParseElectrode(" " + name + " ")
+		if err != nil || e != Electrode(i) || e.String() != name {
+			t.Errorf("%s: got %v %v", name, e, err)
+		}
+	}
+	if _, err := ParseElectrode("VCX"); err == nil {
+		t.Error("expected error for unknown electrode")
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package arcflash
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/readpe/goolx"
+)
+
+// User defined field names for equipment parameters stored on buses, see EquipmentFromUDF. Dimensions are in mm.
+const (
+	FieldElectrode = "AF_ELECTRODE"
+	FieldGap       = "AF_GAP"
+	FieldDistance  = "AF_DISTANCE"
+	FieldHeight    = "AF_HEIGHT"
+	FieldWidth     = "AF_WIDTH"
+	FieldDepth     = "AF_DEPTH"
+)
+
+// Equipment represents a piece of equipment at a bus to be labelled. The voltage and bolted fault current of the
+// parameters are determined from the case.
+type Equipment struct {
+	Name   string
+	BusHnd int
+	Params Params
+
+	// Relay groups clearing an arcing fault at the equipment. If empty, the relay groups at the bus end of every
+	// branch connected to the bus are used.
+	RelayGroups []int
+}
+
+// EquipmentFromUDF loads the equipment parameters from the bus user defined fields. The enclosure dimensions are
+// optional for open air configurations.
+func EquipmentFromUDF(c *goolx.Client, busHnd int) (Equipment, error) {
+	eq := Equipment{BusHnd: busHnd, Name: c.FullBusName(busHnd)}
+	s, err := c.GetUDF(busHnd, FieldElectrode)
+	if err != nil {
+		return eq, fmt.Errorf("EquipmentFromUDF: %v", err)
+	}
+	if eq.Params.Electrode, err = ParseElectrode(s); err != nil {
+		return eq, fmt.Errorf("EquipmentFromUDF: %v", err)
+	}
+	fields := []struct {
+		name     string
+		v        *float64
+		optional bool
+	}{
+		{FieldGap, &eq.Params.Gap, false},
+		{FieldDistance, &eq.Params.Distance, false},
+		{FieldHeight, &eq.Params.Height, eq.Params.Electrode.open()},
+		{FieldWidth, &eq.Params.Width, eq.Params.Electrode.open()},
+		{FieldDepth, &eq.Params.Depth, eq.Params.Electrode.open()},
+	}
+	for _, f := range fields {
+		if *f.v, err = c.GetUDFFloat(busHnd, f.name); err != nil && !f.optional {
+			return eq, fmt.Errorf("EquipmentFromUDF: %v", err)
+		}
+	}
+	return eq, nil
+}
+
+// equipmentHeader is the sidecar equipment file header row.
+var equipmentHeader = []string{"Name", "Bus", "kV", "Electrode", "Gap", "Distance", "Height", "Width", "Depth"}
+
+// ReadEquipmentCSV reads equipment parameters from a sidecar CSV file with the columns Name, Bus, kV, Electrode,
+// Gap, Distance, Height, Width and Depth, and a header row. Buses are found by name and nominal kV, dimensions are
+// in mm and may be left empty for open air configurations.
+func ReadEquipmentCSV(c *goolx.Client, r io.Reader) ([]Equipment, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = len(equipmentHeader)
+	cr.TrimLeadingSpace = true
+	if _, err := cr.Read(); err != nil {
+		return nil, fmt.Errorf("ReadEquipmentCSV: header: %v", err)
+	}
+	var out []Equipment
+	for line := 2; ; line++ {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ReadEquipmentCSV: %v", err)
+		}
+		eq, kv, err := parseEquipment(rec)
+		if err != nil {
+			return nil, fmt.Errorf("ReadEquipmentCSV: line %d: %v", line, err)
+		}
+		if eq.BusHnd, err = c.FindBusByName(rec[1], kv); err != nil {
+			return nil, fmt.Errorf("ReadEquipmentCSV: line %d: %v", line, err)
+		}
+		out = append(out, eq)
+	}
+	return out, nil
+}
+
+// parseEquipment parses the equipment name, parameters and bus kV from a sidecar record.
+func parseEquipment(rec []string) (Equipment, float64, error) {
+	eq := Equipment{Name: strings.TrimSpace(rec[0])}
+	kv, err := strconv.ParseFloat(strings.TrimSpace(rec[2]), 64)
+	if err != nil {
+		return eq, 0, fmt.Errorf("kV: %v", err)
+	}
+	if eq.Params.Electrode, err = ParseElectrode(rec[3]); err != nil {
+		return eq, 0, err
+	}
+	for i, v := range []*float64{&eq.Params.Gap, &eq.Params.Distance, &eq.Params.Height, &eq.Params.Width, &eq.Params.Depth} {
+		s := strings.TrimSpace(rec[4+i])
+		if s == "" && i >= 2 && eq.Params.Electrode.open() {
+			continue
+		}
+		if *v, err = strconv.ParseFloat(s, 64); err != nil {
+			return eq, 0, fmt.Errorf("%s: %v", equipmentHeader[4+i], err)
+		}
+	}
+	return eq, kv, nil
+}
+
+// Study represents an arc flash study configuration.
+type Study struct {
+	MaxTime   float64 // Maximum arc duration in ms, applied when no relay operates, default 2000.
+	Frequency float64 // System frequency for relay group breaker times in cycles, default 60.
+}
+
+// Label represents the arc flash label data for a piece of equipment, at the worst case arcing current.
+type Label struct {
+	Equipment Equipment
+	Evaluation
+}
+
+// Run evaluates the arc flash hazard at each piece of equipment. A bolted 3LG close-in fault is simulated at the
+// equipment bus, clearing previous fault results. The arc duration at each arcing current is the slowest of the
+// clearing relay groups, each clearing at its fastest tripping relay, with relay currents scaled to the arcing
+// current, plus the relay group breaker time.
+func (s Study) Run(c *goolx.Client, equipment []Equipment) ([]Label, error) {
+	if s.MaxTime == 0 {
+		s.MaxTime = 2000
+	}
+	if s.Frequency == 0 {
+		s.Frequency = 60
+	}
+	labels := make([]Label, 0, len(equipment))
+	for _, eq := range equipment {
+		ev, err := s.evaluate(c, eq)
+		if err != nil {
+			return nil, fmt.Errorf("Run: %s: %v", eq.Name, err)
+		}
+		labels = append(labels, Label{Equipment: eq, Evaluation: ev})
+	}
+	return labels, nil
+}
+
+// evaluate simulates the bolted fault at the equipment and evaluates the arc flash hazard.
+func (s Study) evaluate(c *goolx.Client, eq Equipment) (Evaluation, error) {
+	p := eq.Params
+	if err := c.GetData(eq.BusHnd, goolx.BUSdKVnominal).Scan(&p.Voc); err != nil {
+		return Evaluation{}, err
+	}
+	groups := eq.RelayGroups
+	if len(groups) == 0 {
+		groups = busRelayGroups(c, eq.BusHnd)
+	}
+
+	if err := c.DoFault(eq.BusHnd, goolx.NewFaultConfig(goolx.FaultCloseIn(), goolx.FaultConn(goolx.ABC), goolx.FaultClearPrev(true))); err != nil {
+		return Evaluation{}, err
+	}
+	if err := c.PickFault(goolx.SFFirst, 9); err != nil {
+		return Evaluation{}, err
+	}
+	ia, ib, ic, err := c.GetSCCurrentPhase(goolx.HNDSC)
+	if err != nil {
+		return Evaluation{}, err
+	}
+	p.Ibf = math.Max(ia.Mag(), math.Max(ib.Mag(), ic.Mag())) / 1000
+
+	return Evaluate(p, func(iarc float64) (float64, error) {
+		return s.clearingTime(c, groups, iarc/p.Ibf)
+	})
+}
+
+// clearingTime returns the arc duration in ms, with the relay currents scaled by mult.
+func (s Study) clearingTime(c *goolx.Client, groups []int, mult float64) (float64, error) {
+	var t float64
+	for _, hnd := range groups {
+		rg, err := c.GetRelayGroup(hnd)
+		if err != nil {
+			return 0, err
+		}
+		fastest := goolx.RelayNoOpTime
+		for _, d := range rg.Devices() {
+			opTime, _, err := c.GetRelayTime(d.Hnd, mult, true)
+			if err != nil {
+				return 0, err
+			}
+			fastest = math.Min(fastest, opTime)
+		}
+		if fastest >= goolx.RelayNoOpTime {
+			continue
+		}
+		t = math.Max(t, 1000*fastest+1000*rg.BreakerTime/s.Frequency)
+	}
+	if t == 0 || t > s.MaxTime {
+		return s.MaxTime, nil
+	}
+	return t, nil
+}
+
+// busRelayGroups returns the relay groups at the bus end of every branch connected to the bus.
+func busRelayGroups(c *goolx.Client, busHnd int) []int {
+	var groups []int
+	for bi := c.NextBusEquipment(busHnd, goolx.TCBranch); bi.Next(); {
+		var rgHnd int
+		// Ignoring errors on optional data, branches without relay groups are skipped.
+		if err := c.GetData(bi.Hnd(), goolx.BRnRlyGrp1Hnd).Scan(&rgHnd); err == nil && rgHnd != 0 {
+			groups = append(groups, rgHnd)
+		}
+	}
+	return groups
+}
+
+// labelHeader is the label export header row.
+var labelHeader = []string{
+	"Equipment", "kV", "Electrode", "Working Distance (mm)", "Bolted Fault (kA)", "Arcing Current (kA)",
+	"Arc Duration (ms)", "Incident Energy (cal/cm2)", "Incident Energy (J/cm2)", "Arc Flash Boundary (mm)",
+}
+
+// WriteLabelsCSV writes the label data in CSV format, one row per equipment at the worst case arcing current.
+func WriteLabelsCSV(w io.Writer, labels []Label) error {
+	f := func(v float64, prec int) string {
+		return strconv.FormatFloat(v, 'f', prec, 64)
+	}
+	rows := [][]string{labelHeader}
+	for _, l := range labels {
+		worst := l.Worst()
+		rows = append(rows, []string{
+			l.Equipment.Name, f(l.Params.Voc, 3), l.Params.Electrode.String(), f(l.Params.Distance, 1),
+			f(l.Params.Ibf, 3), f(worst.Iarc, 3), f(worst.Time, 1),
+			f(worst.EnergyCal(), 2), f(worst.Energy, 2), f(worst.Boundary, 0),
+		})
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(rows); err != nil {
+		return fmt.Errorf("WriteLabelsCSV: %v", err)
+	}
+	return nil
+}
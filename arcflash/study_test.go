@@ -0,0 +1,72 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package arcflash
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"github.com/readpe/goolx"
+)
+
+var testCase = `C:\Program Files (x86)\ASPEN\1LPFv15\SAMPLE09.OLR`
+
+func TestParseEquipment(t *testing.T) {
+	eq, kv, err := parseEquipment([]string{"SWGR-1", "NEVADA", "13.8", "vcb", "152", "914.4", "1143", "762", "762"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if eq.Name != "SWGR-1" || kv != 13.8 || eq.Params.Electrode != VCB || eq.Params.Width != 762 {
+		t.Errorf("unexpected equipment %+v %v", eq, kv)
+	}
+
+	// Open air configurations do not require enclosure dimensions.
+	if _, _, err := parseEquipment([]string{"BUS-1", "OHIO", "13.8", "VOA", "152", "914.4", "", "", ""}); err != nil {
+		t.Error(err)
+	}
+	if _, _, err := parseEquipment([]string{"BUS-1", "OHIO", "13.8", "VCB", "152", "914.4", "", "", ""}); err == nil {
+		t.Error("expected error for missing enclosure dimensions")
+	}
+}
+
+func TestWriteLabelsCSV(t *testing.T) {
+	p := Params{Electrode: VCB, Voc: 4.16, Ibf: 15, Gap: 104, Distance: 914.4, Height: 1143, Width: 762, Depth: 762}
+	ev, err := Evaluate(p, FixedTime(197))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := WriteLabelsCSV(&buf, []Label{{Equipment: Equipment{Name: "SWGR-1"}, Evaluation: ev}}); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || len(rows[1]) != len(labelHeader) {
+		t.Fatalf("unexpected rows %v", rows)
+	}
+	if rows[1][0] != "SWGR-1" || rows[1][2] != "VCB" || rows[1][5] != "12.979" || rows[1][7] != "2.90" {
+		t.Errorf("unexpected label %v", rows[1])
+	}
+}
+
+func TestClient_Study(t *testing.T) {
+	c := goolx.NewClient()
+	defer c.Release()
+	if err := c.LoadDataFile(testCase); err != nil {
+		t.Fatal(err)
+	}
+	busHnd, err := c.FindBusByName("NEVADA", 132)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Transmission voltages are outside the model range.
+	eq := Equipment{Name: "NEVADA", BusHnd: busHnd, Params: Params{Electrode: VOA, Gap: 152, Distance: 914.4}}
+	if _, err := (Study{}).Run(c, []Equipment{eq}); err == nil {
+		t.Error("expected error for voltage outside model range")
+	}
+}
@@ -0,0 +1,242 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// In-service flag values for equipment InService and Online parameters.
+const (
+	InServiceOn  = 1
+	InServiceOff = 2
+)
+
+// serviceTokens represents the on date, off date and in-service flag parameter tokens for an equipment type.
+type serviceTokens struct {
+	onDate, offDate, flag int
+}
+
+// inServiceTokens maps equipment types to their in-service date and flag tokens. Equipment types not
+// within the map carry no in-service dates and are always considered in service.
+var inServiceTokens = map[int]serviceTokens{
+	TCLine:      {LNsOnDate, LNsOffDate, LNnInService},
+	TCXFMR:      {XRsOnDate, XRsOffDate, XRnInService},
+	TCXFMR3:     {X3sOnDate, X3sOffDate, X3nInService},
+	TCPS:        {PSsOnDate, PSsOffDate, PSnInService},
+	TCSCAP:      {SCsOnDate, SCsOffDate, SCnInService},
+	TCSwitch:    {SWsOnDate, SWsOffDate, SWnInService},
+	TCGenUnit:   {GUsOnDate, GUsOffDate, GUnOnline},
+	TCShuntUnit: {SUsOnDate, SUsOffDate, SUnOnline},
+	TCLoadUnit:  {LUsOnDate, LUsOffDate, LUnOnline},
+	TCCCGEN:     {CCsOnDate, CCsOffDate, CCnInService},
+}
+
+// datedTypes lists the equipment types within inServiceTokens in a deterministic order.
+var datedTypes = []int{TCLine, TCXFMR, TCXFMR3, TCPS, TCSCAP, TCSwitch, TCGenUnit, TCShuntUnit, TCLoadUnit, TCCCGEN}
+
+// equipmentDateLayouts are the layouts accepted when parsing equipment on and off dates.
+var equipmentDateLayouts = []string{"2006/1/2", "2006-01-02", "1/2/2006"}
+
+// parseEquipmentDate parses an equipment on or off date string, returns the zero time if empty.
+func parseEquipmentDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range equipmentDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unable to parse equipment date %q", s)
+}
+
+// inServiceOn reports whether equipment with the provided on and off dates is in service on the date.
+// Equipment is in service from the on date, up to but excluding the off date. Empty dates are unbounded.
+func inServiceOn(onDate, offDate string, date time.Time) (bool, error) {
+	on, err := parseEquipmentDate(onDate)
+	if err != nil {
+		return false, err
+	}
+	off, err := parseEquipmentDate(offDate)
+	if err != nil {
+		return false, err
+	}
+	d := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	if !on.IsZero() && d.Before(on) {
+		return false, nil
+	}
+	if !off.IsZero() && !d.Before(off) {
+		return false, nil
+	}
+	return true, nil
+}
+
+// AsOf represents a view of the case as of a given date. Iterators and equipment loaders skip equipment
+// which is not in service on the date according to the equipment on and off dates. Obtain using Client.AsOf.
+type AsOf struct {
+	c    *Client
+	date time.Time
+}
+
+// AsOf returns a view of the case as of the provided date.
+func (c *Client) AsOf(date time.Time) *AsOf {
+	return &AsOf{c: c, date: date}
+}
+
+// Date returns the view date.
+func (a *AsOf) Date() time.Time {
+	return a.date
+}
+
+// equipment returns the equipment handle and type, resolving branch handles to the branch equipment.
+func (a *AsOf) equipment(hnd int) (int, int, error) {
+	eqType, err := a.c.EquipmentType(hnd)
+	if err != nil {
+		return 0, 0, err
+	}
+	if eqType == TCBranch {
+		if err := a.c.GetData(hnd, BRnHandle).Scan(&hnd); err != nil {
+			return 0, 0, err
+		}
+		if eqType, err = a.c.EquipmentType(hnd); err != nil {
+			return 0, 0, err
+		}
+	}
+	return hnd, eqType, nil
+}
+
+// InService reports whether the equipment with the provided handle is in service on the view date, based on
+// the equipment on and off dates. Branch handles are resolved to the branch equipment. Equipment types
+// without in-service dates are always in service.
+func (a *AsOf) InService(hnd int) (bool, error) {
+	hnd, eqType, err := a.equipment(hnd)
+	if err != nil {
+		return false, err
+	}
+	tkns, ok := inServiceTokens[eqType]
+	if !ok {
+		return true, nil
+	}
+	var onDate, offDate string
+	if err := a.c.GetData(hnd, tkns.onDate, tkns.offDate).Scan(&onDate, &offDate); err != nil {
+		return false, err
+	}
+	inService, err := inServiceOn(onDate, offDate, a.date)
+	if err != nil {
+		return false, fmt.Errorf("InService: %s: %v", a.c.printID(hnd), err)
+	}
+	return inService, nil
+}
+
+// filter wraps the provided iterator, skipping equipment not in service on the view date.
+func (a *AsOf) filter(hi HandleIterator) HandleIterator {
+	return &handleIterator{
+		f: func(hnd *int) error {
+			for hi.Next() {
+				if ok, _ := a.InService(hi.Hnd()); ok {
+					*hnd = hi.Hnd()
+					return nil
+				}
+			}
+			return fmt.Errorf("iteration exhausted")
+		},
+	}
+}
+
+// NextEquipment returns a HandleIterator for all equipment of the provided type in service on the view date.
+// See Client.NextEquipment for more details.
+func (a *AsOf) NextEquipment(eqType int) HandleIterator {
+	return a.filter(a.c.NextEquipment(eqType))
+}
+
+// NextBusEquipment returns a HandleIterator for all equipment of the provided type at the bus in service on
+// the view date. See Client.NextBusEquipment for more details.
+func (a *AsOf) NextBusEquipment(busHnd, eqType int) HandleIterator {
+	return a.filter(a.c.NextBusEquipment(busHnd, eqType))
+}
+
+// NextEquipmentByTag returns a HandleIterator for all equipment of the provided type with the tags in service
+// on the view date. See Client.NextEquipmentByTag for more details.
+func (a *AsOf) NextEquipmentByTag(eqType int, tags ...string) HandleIterator {
+	return a.filter(a.c.NextEquipmentByTag(eqType, tags...))
+}
+
+// checkInService returns an error if the equipment is not in service on the view date.
+func (a *AsOf) checkInService(hnd int) error {
+	ok, err := a.InService(hnd)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%s not in service on %s", a.c.printID(hnd), a.date.Format("2006-01-02"))
+	}
+	return nil
+}
+
+// GetLine loads the line data at the provided handle. Returns an error if the line is not in service on the
+// view date. See Client.GetLine.
+func (a *AsOf) GetLine(hnd int) (*Line, error) {
+	if err := a.checkInService(hnd); err != nil {
+		return nil, fmt.Errorf("GetLine: %v", err)
+	}
+	return a.c.GetLine(hnd)
+}
+
+// Apply sets the in-service flag of all dated equipment in the case to match the view date, e.g. prior to
+// running DoFault. The returned restore function restores the original in-service flags, and should be called
+// once done with the results. Changes are made within a transaction, see Client.Begin.
+func (a *AsOf) Apply() (restore func() error, err error) {
+	tx := a.c.Begin()
+	for _, eqType := range datedTypes {
+		tkns := inServiceTokens[eqType]
+		for ei := a.c.NextEquipment(eqType); ei.Next(); {
+			hnd := ei.Hnd()
+			var onDate, offDate string
+			var flag int
+			if err := a.c.GetData(hnd, tkns.onDate, tkns.offDate, tkns.flag).Scan(&onDate, &offDate, &flag); err != nil {
+				return nil, fmt.Errorf("Apply: %s: %v", a.c.printID(hnd), err)
+			}
+			inService, err := inServiceOn(onDate, offDate, a.date)
+			if err != nil {
+				return nil, fmt.Errorf("Apply: %s: %v", a.c.printID(hnd), err)
+			}
+			want := InServiceOff
+			if inService {
+				want = InServiceOn
+			}
+			if flag == want {
+				continue
+			}
+			if err := tx.SetData(hnd, tkns.flag, want); err != nil {
+				return nil, fmt.Errorf("Apply: %s: %v", a.c.printID(hnd), err)
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("Apply: %v", err)
+	}
+	return tx.Rollback, nil
+}
+
+// DoFault applies the view date in-service flags and runs the fault, see Apply and Client.DoFault. The returned
+// restore function restores the original in-service flags, and should be called once done with the results.
+// The flags are restored immediately if the fault fails.
+func (a *AsOf) DoFault(hnd int, config *FaultConfig) (restore func() error, err error) {
+	restore, err = a.Apply()
+	if err != nil {
+		return nil, err
+	}
+	if err := a.c.DoFault(hnd, config); err != nil {
+		if rErr := restore(); rErr != nil {
+			return nil, fmt.Errorf("DoFault: %v; restore failed: %v", err, rErr)
+		}
+		return nil, err
+	}
+	return restore, nil
+}
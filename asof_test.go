@@ -0,0 +1,87 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_inServiceOn(t *testing.T) {
+	date := time.Date(2025, 6, 1, 13, 30, 0, 0, time.Local)
+	tests := []struct {
+		name     string
+		on, off  string
+		expected bool
+		err      bool
+	}{
+		{name: "no dates", expected: true},
+		{name: "on before", on: "2020/1/1", expected: true},
+		{name: "on same day", on: "2025/6/1", expected: true},
+		{name: "on after", on: "2026-01-01", expected: false},
+		{name: "off after", off: "2025/6/2", expected: true},
+		{name: "off same day", off: "2025/6/1", expected: false},
+		{name: "window", on: "1/1/2024", off: "1/1/2026", expected: true},
+		{name: "retired", on: "1/1/2000", off: "1/1/2024", expected: false},
+		{name: "malformed", on: "next year", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := inServiceOn(tt.on, tt.off, date)
+			if (err != nil) != tt.err {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestClient_AsOf(t *testing.T) {
+	c := NewClient()
+	defer c.Release()
+	if err := c.LoadDataFile(testCase); err != nil {
+		t.Fatal(err)
+	}
+
+	ln, err := c.FindLine("CLAYTOR", 132, "NEVADA", 132, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetData(ln.Hnd, LNsOffDate, "2020/1/1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.PostData(ln.Hnd); err != nil {
+		t.Fatal(err)
+	}
+
+	view := c.AsOf(time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local))
+	for li := view.NextEquipment(TCLine); li.Next(); {
+		if li.Hnd() == ln.Hnd {
+			t.Errorf("expected retired line to be skipped")
+		}
+	}
+	if _, err := view.GetLine(ln.Hnd); err == nil {
+		t.Errorf("expected not in service error, got nil")
+	}
+
+	restore, err := view.Apply()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var flag int
+	c.GetData(ln.Hnd, LNnInService).Scan(&flag)
+	if flag != InServiceOff {
+		t.Errorf("expected in service flag %d, got %d", InServiceOff, flag)
+	}
+	if err := restore(); err != nil {
+		t.Fatal(err)
+	}
+	c.GetData(ln.Hnd, LNnInService).Scan(&flag)
+	if flag != InServiceOn {
+		t.Errorf("expected in service flag %d, got %d", InServiceOn, flag)
+	}
+}
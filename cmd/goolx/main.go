@@ -0,0 +1,118 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+// Command goolx provides command line tools for ASPEN Oneliner cases.
+//
+// Usage:
+//
+//	goolx lint [flags] case.olr
+//
+// The lint command checks the case for common modeling errors, see package lint. The exit status is 1 if any
+// error severity findings are reported.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/readpe/goolx"
+	"github.com/readpe/goolx/lint"
+)
+
+const usage = `Usage: goolx <command> [flags] [args]
+
+Commands:
+  lint    check a case for common modeling errors
+
+Run 'goolx <command> -h' for command flags.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	switch os.Args[1] {
+	case "lint":
+		os.Exit(runLint(os.Args[2:], os.Stdout, os.Stderr))
+	case "-h", "-help", "--help", "help":
+		fmt.Fprint(os.Stdout, usage)
+	default:
+		fmt.Fprintf(os.Stderr, "goolx: unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+}
+
+// runLint runs the lint command with the provided arguments, returning the exit status.
+func runLint(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	format := fs.String("format", lint.FormatText, "output format: text, json or sarif")
+	severity := fs.String("severity", "info", "minimum reported severity: info, warning or error")
+	rules := fs.String("rules", "", "comma separated list of rules to run, default all")
+	list := fs.Bool("list", false, "list available rules and exit")
+	out := fs.String("o", "", "output file, default stdout")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: goolx lint [flags] case.olr\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	linter := lint.Default()
+	if *list {
+		for _, r := range linter.Rules() {
+			fmt.Fprintf(stdout, "%-24s %-8s %s\n", r.Name, r.Severity, r.Description)
+		}
+		return 0
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	minSev, err := lint.ParseSeverity(*severity)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	if *rules != "" {
+		if linter, err = linter.Only(strings.Split(*rules, ",")...); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+	}
+
+	w := stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+		defer f.Close()
+		w = f
+	}
+
+	c := goolx.NewClient()
+	defer c.Release()
+	if err := c.LoadDataFileReadOnly(fs.Arg(0)); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	report := linter.Run(c).Filter(minSev)
+	report.Sort()
+	if err := report.Write(w, *format); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	if report.Count(lint.SeverityError) > 0 {
+		return 1
+	}
+	return 0
+}
@@ -83,6 +83,7 @@ const (
 	MXZONE     = 8
 	MAXCCV     = 10
 	MXSBKF     = 10
+	MXMUSECT   = 5 // Mutual pair sections, MUvdX, MUvdR, MUvdFrom1/2 and MUvdTo1/2 are arrays of 5 (OlxAPI Reference Manual, mutual pair data).
 )
 
 // Parameter tokens
@@ -717,4 +718,12 @@ var ArrayLengths = map[int]map[int]int{
 		DPvdReach:  MXZONE,
 		DPvdReach1: MXZONE,
 	},
+	TCMU: {
+		MUvdX:     MXMUSECT,
+		MUvdR:     MXMUSECT,
+		MUvdFrom1: MXMUSECT,
+		MUvdFrom2: MXMUSECT,
+		MUvdTo1:   MXMUSECT,
+		MUvdTo2:   MXMUSECT,
+	},
 }
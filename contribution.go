@@ -0,0 +1,207 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ContributionNode represents a fault current contribution into its parent bus, either through a branch from a
+// remote bus or from a source at the parent bus. The root node represents the faulted bus and total fault current.
+type ContributionNode struct {
+	BusHnd int    // Remote bus handle, zero for sources.
+	EqHnd  int    // Branch or source equipment handle, zero for the root.
+	EqType int    // Branch or source equipment type.
+	Label  string // Bus, branch or source description.
+
+	Current Phasor // Contribution of the faulted phase into the parent bus, in amps.
+	Share   Phasor // Contribution relative to the total fault current.
+
+	Loop     bool // Branch to a bus already within the tree, not expanded further.
+	Children []*ContributionNode
+}
+
+// Percent returns the contribution magnitude as a percentage of the total fault current magnitude.
+func (n *ContributionNode) Percent() float64 {
+	return 100 * n.Share.Mag()
+}
+
+// ContributionTree represents the fault current contributions into a faulted bus, see Client.ContributionTree.
+type ContributionTree struct {
+	Root  *ContributionNode
+	Phase int // Faulted phase index, 0 for phase A.
+	Total Phasor
+}
+
+// Walk calls f for each node in the tree, depth first, along with its depth.
+func (t *ContributionTree) Walk(f func(n *ContributionNode, depth int)) {
+	var walk func(n *ContributionNode, depth int)
+	walk = func(n *ContributionNode, depth int) {
+		f(n, depth)
+		for _, child := range n.Children {
+			walk(child, depth+1)
+		}
+	}
+	walk(t.Root, 0)
+}
+
+// WriteText writes the tree as indented text, one contribution per line.
+func (t *ContributionTree) WriteText(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	t.Walk(func(n *ContributionNode, depth int) {
+		loop := ""
+		if n.Loop {
+			loop = " (loop)"
+		}
+		fmt.Fprintf(bw, "%s%s %s %0.1f%%%s\n", strings.Repeat("  ", depth), n.Label, n.Current, n.Percent(), loop)
+	})
+	return bw.Flush()
+}
+
+// WriteDOT writes the tree in Graphviz DOT format, with edges directed toward the faulted bus.
+func (t *ContributionTree) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph contributions {")
+	fmt.Fprintln(bw, "\trankdir=RL;")
+	id := 0
+	var write func(n *ContributionNode) int
+	write = func(n *ContributionNode) int {
+		nid := id
+		id++
+		shape := "box"
+		if n.BusHnd == 0 && n != t.Root {
+			shape = "ellipse"
+		}
+		fmt.Fprintf(bw, "\tn%d [shape=%s, label=%q];\n", nid, shape, n.Label)
+		for _, child := range n.Children {
+			cid := write(child)
+			fmt.Fprintf(bw, "\tn%d -> n%d [label=%q];\n", cid, nid, fmt.Sprintf("%s %0.1f%%", child.Current, child.Percent()))
+		}
+		return nid
+	}
+	write(t.Root)
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// newContributionNode returns a contribution node for the current into the parent bus, relative to the total.
+func newContributionNode(busHnd, eqHnd, eqType int, label string, current, total Phasor) *ContributionNode {
+	n := &ContributionNode{BusHnd: busHnd, EqHnd: eqHnd, EqType: eqType, Label: label, Current: current}
+	if total != 0 {
+		n.Share = current / total
+	}
+	return n
+}
+
+// ContributionTree walks the topology outward from the faulted bus for the provided number of tiers, building a
+// tree of the branch and source fault current contributions into each bus. Sources are the generators, converter
+// generators, SVDs, shunts and loads connected at the bus, see contributionSources. Contributions are for the faulted
+// phase, the phase with the largest total fault current. PickFault must be called first with at least as many
+// tiers.
+//
+// Oneliner reports branch currents flowing from the terminal bus into the branch, branch contributions into a bus
+// are the negated branch current at that bus. Source currents are reported flowing into the bus, grounded shunts
+// and loads contribute the zero sequence current of their ground paths.
+func (c *Client) ContributionTree(busHnd, tiers int) (*ContributionTree, error) {
+	var total [3]Phasor
+	var err error
+	if total[0], total[1], total[2], err = c.GetSCCurrentPhase(HNDSC); err != nil {
+		return nil, fmt.Errorf("ContributionTree: %v", err)
+	}
+	t := &ContributionTree{}
+	for p := 1; p < 3; p++ {
+		if total[p].Mag() > total[t.Phase].Mag() {
+			t.Phase = p
+		}
+	}
+	t.Total = total[t.Phase]
+	t.Root = newContributionNode(busHnd, 0, TCBus, c.FullBusName(busHnd), t.Total, t.Total)
+
+	type item struct {
+		node  *ContributionNode
+		eqHnd int // Equipment the bus was reached through.
+	}
+	seen := map[int]bool{busHnd: true}
+	frontier := []item{{t.Root, 0}}
+	for tier := 0; tier < tiers && len(frontier) > 0; tier++ {
+		var next []item
+		for _, it := range frontier {
+			children, err := c.busContributions(it.node.BusHnd, it.eqHnd, t.Phase, t.Total)
+			if err != nil {
+				return nil, fmt.Errorf("ContributionTree: %v", err)
+			}
+			for _, child := range children {
+				if child.BusHnd != 0 {
+					if seen[child.BusHnd] {
+						child.Loop = true
+					} else {
+						seen[child.BusHnd] = true
+						next = append(next, item{child, child.EqHnd})
+					}
+				}
+				it.node.Children = append(it.node.Children, child)
+			}
+		}
+		frontier = next
+	}
+	return t, nil
+}
+
+// contributionSources are the bus connected source equipment types and labels included in contribution trees.
+var contributionSources = []struct {
+	eqType int
+	label  string
+}{
+	{TCGen, "Generator"},
+	{TCCCGEN, "Converter Generator"},
+	{TCSVD, "SVD"},
+	{TCShunt, "Shunt"},
+	{TCLoad, "Load"},
+}
+
+// busContributions returns the source and branch contributions into the bus, excluding the branch equipment the
+// bus was reached through.
+func (c *Client) busContributions(busHnd, parentEqHnd, phase int, total Phasor) ([]*ContributionNode, error) {
+	var nodes []*ContributionNode
+	for _, src := range contributionSources {
+		for ei := c.NextBusEquipment(busHnd, src.eqType); ei.Next(); {
+			i, err := c.GetSCCurrentTerminals(ei.Hnd(), SCPhaseRect)
+			if err != nil {
+				return nil, err
+			}
+			if len(i.Terminals) == 0 {
+				continue
+			}
+			label := src.label + " " + c.FullBusName(busHnd)
+			nodes = append(nodes, newContributionNode(0, ei.Hnd(), src.eqType, label, i.Terminals[0].Values[phase], total))
+		}
+	}
+	for bi := c.NextBusEquipment(busHnd, TCBranch); bi.Next(); {
+		var bus2Hnd, eqHnd int
+		if err := c.GetData(bi.Hnd(), BRnBus2Hnd, BRnHandle).Scan(&bus2Hnd, &eqHnd); err != nil {
+			return nil, err
+		}
+		if eqHnd == parentEqHnd {
+			continue
+		}
+		eqType, err := c.EquipmentType(eqHnd)
+		if err != nil {
+			return nil, err
+		}
+		i, err := c.GetSCCurrentTerminals(eqHnd, SCPhaseRect)
+		if err != nil {
+			return nil, err
+		}
+		term, ok := i.Terminal(busHnd)
+		if !ok {
+			return nil, fmt.Errorf("no current at bus %s for %s", c.FullBusName(busHnd), c.FullBranchName(bi.Hnd()))
+		}
+		nodes = append(nodes, newContributionNode(bus2Hnd, eqHnd, eqType, c.FullBranchName(bi.Hnd()), -term.Values[phase], total))
+	}
+	return nodes, nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func testContributionTree() *ContributionTree {
+	total := NewPhasor(1000, -80)
+	root := newContributionNode(1, 0, TCBus, "NEVADA 132kV", total, total)
+	line := newContributionNode(2, 10, TCLine, "NEVADA-CLAYTOR 1L", NewPhasor(600, -80), total)
+	gen := newContributionNode(0, 20, TCGen, "Generator CLAYTOR 132kV", NewPhasor(450, -85), total)
+	loop := newContributionNode(3, 11, TCLine, "CLAYTOR-OHIO 1L", NewPhasor(150, -70), total)
+	loop.Loop = true
+	line.Children = []*ContributionNode{gen, loop}
+	root.Children = []*ContributionNode{line, newContributionNode(3, 12, TCLine, "NEVADA-OHIO 1L", NewPhasor(400, -80), total)}
+	return &ContributionTree{Root: root, Total: total}
+}
+
+func TestContributionNode_Percent(t *testing.T) {
+	tree := testContributionTree()
+	if got := tree.Root.Percent(); !almostEqual(got, 100) {
+		t.Errorf("expected root 100%%, got %v", got)
+	}
+	line := tree.Root.Children[0]
+	if got := line.Percent(); !almostEqual(got, 60) {
+		t.Errorf("expected 60%%, got %v", got)
+	}
+	if got := line.Share.Ang(); got > 1e-9 || got < -1e-9 {
+		t.Errorf("expected in phase contribution, got %v", got)
+	}
+}
+
+func TestContributionTree_WriteText(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testContributionTree().WriteText(&buf); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("expected 5 lines, got %q", buf.String())
+	}
+	expected := []string{
+		"NEVADA 132kV",
+		"  NEVADA-CLAYTOR 1L",
+		"    Generator CLAYTOR 132kV",
+		"    CLAYTOR-OHIO 1L",
+		"  NEVADA-OHIO 1L",
+	}
+	for i, prefix := range expected {
+		if !strings.HasPrefix(lines[i], prefix) {
+			t.Errorf("line %d: expected prefix %q, got %q", i, prefix, lines[i])
+		}
+	}
+	if !strings.HasSuffix(lines[3], "15.0% (loop)") {
+		t.Errorf("expected loop marker, got %q", lines[3])
+	}
+}
+
+func TestContributionTree_WriteDOT(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testContributionTree().WriteDOT(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, s := range []string{"digraph contributions {", `n0 [shape=box, label="NEVADA 132kV"]`, "n1 -> n0", "n2 [shape=ellipse", "n4 -> n0"} {
+		if !strings.Contains(out, s) {
+			t.Errorf("expected %q in output:\n%s", s, out)
+		}
+	}
+}
+
+func TestClient_ContributionTree(t *testing.T) {
+	c := NewClient()
+	defer c.Release()
+	if err := c.LoadDataFile(testCase); err != nil {
+		t.Fatal(err)
+	}
+	busHnd, err := c.FindBusByName("NEVADA", 132)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DoFault(busHnd, NewFaultConfig(FaultCloseIn(), FaultConn(AG))); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.PickFault(SFFirst, 3); err != nil {
+		t.Fatal(err)
+	}
+	tree, err := c.ContributionTree(busHnd, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tree.Root.Children) == 0 {
+		t.Fatal("expected contributions into faulted bus")
+	}
+
+	// Contributions into the faulted bus sum to the total fault current.
+	var sum Phasor
+	for _, n := range tree.Root.Children {
+		sum += n.Current
+	}
+	if d := (sum - tree.Total).Mag(); d > 0.01*tree.Total.Mag() {
+		t.Errorf("expected contributions to sum to %v, got %v", tree.Total, sum)
+	}
+}
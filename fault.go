@@ -281,9 +281,10 @@ func FaultIntermediateEndOpenOutage(percent float64, outageList []int, otgOpt Ou
 }
 
 // FaultIntermediateAuto applies an auto sequencing intermediate fault between from and to at the specified step.
+// The step shares the intermediate fault percentage option, see FaultIntermediate.
 func FaultIntermediateAuto(step, from, to float64) FaultOption {
 	return func(cfg *FaultConfig) {
-		cfg.fltOpt[9] = step
+		cfg.fltOpt[8] = step
 		cfg.fltOpt[12] = from
 		cfg.fltOpt[13] = to
 	}
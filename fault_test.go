@@ -0,0 +1,18 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import "testing"
+
+func TestFaultIntermediateAuto(t *testing.T) {
+	cfg := NewFaultConfig(FaultIntermediateAuto(5, 10, 90))
+	var want [15]float64
+	want[8], want[12], want[13] = 5, 10, 90
+	for i, v := range want {
+		if cfg.fltOpt[i] != v {
+			t.Errorf("fltOpt[%d]: expected %v, got %v", i, v, cfg.fltOpt[i])
+		}
+	}
+}
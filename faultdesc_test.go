@@ -0,0 +1,217 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// faultDescriptionGoldenFile holds the fault descriptions captured from Oneliner, see
+// TestClient_FaultDescriptionGoldens.
+const faultDescriptionGoldenFile = "testdata/fault_descriptions.golden"
+
+// readGoldens reads the option keys and descriptions of a golden file, in file order.
+func readGoldens(path string) (keys []string, descs map[string]string, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	descs = make(map[string]string)
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := s.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, quoted, ok := strings.Cut(line, " ")
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid golden line %q", line)
+		}
+		desc, err := strconv.Unquote(quoted)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %v", key, err)
+		}
+		keys = append(keys, key)
+		descs[key] = desc
+	}
+	return keys, descs, s.Err()
+}
+
+// writeGoldens writes the option keys and descriptions to a golden file, keeping its header comments.
+func writeGoldens(path string, keys []string, descs map[string]string) error {
+	var header []string
+	if b, err := os.ReadFile(path); err == nil {
+		for _, line := range strings.Split(string(b), "\n") {
+			if strings.HasPrefix(line, "#") {
+				header = append(header, line)
+			}
+		}
+	}
+	var sb strings.Builder
+	for _, line := range header {
+		sb.WriteString(line + "\n")
+	}
+	for _, key := range keys {
+		fmt.Fprintf(&sb, "%s %s\n", key, strconv.Quote(descs[key]))
+	}
+	return os.WriteFile(path, []byte(sb.String()), 0644)
+}
+
+var (
+	tennessee = BusRef{Number: 4, Name: "TENNESSEE", KV: 132}
+	roanoke   = BusRef{Number: 11, Name: "ROANOKE", KV: 13.8}
+	newHamp   = BusRef{Number: 10, Name: "NEW HAMPSHR", KV: 33}
+)
+
+// faultDescriptionWants are the expected parse results of golden fault descriptions, by option key.
+var faultDescriptionWants = map[string]FaultInfo{
+	"FaultCloseIn/bus/3LG":    {Index: 1, Bus: tennessee, Conn: ABC},
+	"FaultCloseIn/bus/1LG":    {Index: 1, Bus: tennessee, Conn: AG},
+	"FaultCloseIn/bus/13.8kV": {Index: 1, Bus: roanoke, Conn: ABC},
+	"FaultCloseIn/bus/33kV":   {Index: 2, Bus: newHamp, Conn: AG},
+	"SteppedEventCloseIn/3LG": {Index: 1, Simultaneous: true, Bus: tennessee, Conn: ABC},
+}
+
+func TestParseFaultDescription(t *testing.T) {
+	keys, descs, err := readGoldens(faultDescriptionGoldenFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range keys {
+		got, err := ParseFaultDescription(descs[key])
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", key, err)
+			continue
+		}
+		want, ok := faultDescriptionWants[key]
+		if !ok {
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: %q\nexpected %+v\ngot      %+v", key, descs[key], want, got)
+		}
+	}
+}
+
+// TestParseFaultDescription_Connections covers the connection codes, substituted into a captured 1LG description.
+func TestParseFaultDescription_Connections(t *testing.T) {
+	for code, conn := range faultConns {
+		code = strings.Replace(code, " ", " Type=", 1)
+		desc := "1. Bus Fault on:           4 TENNESSEE        132. kV " + code
+		got, err := ParseFaultDescription(desc)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", code, err)
+			continue
+		}
+		if got.Conn != conn {
+			t.Errorf("%s: expected connection %v, got %v", code, conn, got.Conn)
+		}
+	}
+}
+
+func TestParseFaultDescription_Errors(t *testing.T) {
+	tests := []string{
+		"",
+		"Bus Fault on: 4 TENNESSEE 132. kV 3LG",
+		"1. Bus Fault 4 TENNESSEE 132. kV 3LG",
+		"1. Unknown Fault on: 4 TENNESSEE 132. kV 3LG",
+		"1. Bus Fault on: 4 TENNESSEE 132. kV",
+		"1. Bus Fault on: 4 TENNESSEE 132. kV 2LG Type=A",
+		"1. Bus Fault on: TENNESSEE 132. kV 3LG",
+		"1. Close-In Fault on: 4 TENNESSEE 132. kV - 6 NEVADA 132. kV 1L 3LG",
+		"1. Bus Fault on: 4 TENNESSEE 132. kV 1LG Type=A R=2 X=2",
+		"1. Bus Fault on: 4 TENNESSEE 132. kV 3LG w/ 4 TENNESSEE 132. kV - 6 NEVADA 132. kV 1L",
+	}
+	for _, desc := range tests {
+		if f, err := ParseFaultDescription(desc); err == nil {
+			t.Errorf("%q: expected error, got %+v", desc, f)
+		}
+	}
+}
+
+// TestClient_FaultDescriptionGoldens runs bus faults on the test case, checks the parsed description against
+// the applied options and compares the description with the golden file. Run with -update to capture the golden
+// descriptions.
+func TestClient_FaultDescriptionGoldens(t *testing.T) {
+	c := NewClient()
+	defer c.Release()
+	if err := c.LoadDataFile(testCase); err != nil {
+		t.Fatal(err)
+	}
+	bus := func(name string, kv float64) int {
+		hnd, err := c.FindBusByName(name, kv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hnd
+	}
+
+	tests := []struct {
+		key     string
+		hnd     int
+		cfg     *FaultConfig
+		stepped *SteppedEventConfig
+		index   int
+		conn    FltConn
+	}{
+		{"FaultCloseIn/bus/3LG", bus("TENNESSEE", 132), NewFaultConfig(FaultCloseIn(), FaultConn(ABC)), nil, 1, ABC},
+		{"FaultCloseIn/bus/1LG", bus("TENNESSEE", 132), NewFaultConfig(FaultCloseIn(), FaultConn(AG)), nil, 1, AG},
+		{"FaultCloseIn/bus/13.8kV", bus("ROANOKE", 13.8), NewFaultConfig(FaultCloseIn(), FaultConn(ABC)), nil, 1, ABC},
+		{"FaultCloseIn/bus/33kV", bus("NEW HAMPSHR", 33), NewFaultConfig(FaultCloseIn(), FaultConn(ABC, AG)), nil, 2, AG},
+		{"SteppedEventCloseIn/3LG", bus("TENNESSEE", 132), nil, NewSteppedEvent(SteppedEventConn(ABC), SteppedEventAll(), SteppedEventCloseIn()), 1, ABC},
+	}
+
+	keys, goldens, err := readGoldens(faultDescriptionGoldenFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		if tt.cfg != nil {
+			FaultClearPrev(true)(tt.cfg)
+			err = c.DoFault(tt.hnd, tt.cfg)
+		} else {
+			err = c.DoSteppedEvent(tt.hnd, tt.stepped)
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.key, err)
+			continue
+		}
+		desc := c.FaultDescription(tt.index)
+		if *update {
+			if _, ok := goldens[tt.key]; !ok {
+				keys = append(keys, tt.key)
+			}
+			goldens[tt.key] = desc
+		} else if want, ok := goldens[tt.key]; !ok {
+			t.Errorf("%s: missing golden description, got %q", tt.key, desc)
+		} else if desc != want {
+			t.Errorf("%s: expected %q, got %q", tt.key, want, desc)
+		}
+
+		f, err := c.FaultInfo(tt.index)
+		if err != nil {
+			t.Errorf("%s: %v", tt.key, err)
+			continue
+		}
+		if f.Index != tt.index || f.Conn != tt.conn || f.Simultaneous != (tt.stepped != nil) {
+			t.Errorf("%s: %q: unexpected %+v", tt.key, desc, f)
+		}
+	}
+	if *update {
+		if err := writeGoldens(faultDescriptionGoldenFile, keys, goldens); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
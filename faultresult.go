@@ -0,0 +1,264 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// SeqPhase represents a three phase quantity in both sequence (0, 1, 2) and phase (A, B, C) form.
+type SeqPhase struct {
+	Seq   SeqSet   `json:"seq"`
+	Phase PhaseSet `json:"phase"`
+}
+
+// equal reports whether the quantities are equal within the absolute tolerance.
+func (s SeqPhase) equal(o SeqPhase, tol float64) bool {
+	for i := 0; i < 3; i++ {
+		if (s.Seq[i]-o.Seq[i]).Mag() > tol || (s.Phase[i]-o.Phase[i]).Mag() > tol {
+			return false
+		}
+	}
+	return true
+}
+
+// faultProbes represents the quantities captured by SnapshotFault.
+type faultProbes struct {
+	tiers    int
+	buses    []int
+	currents []int
+	around   []struct{ bus, tiers int }
+}
+
+// FaultProbe represents a quantity captured by SnapshotFault.
+type FaultProbe func(*faultProbes)
+
+// ProbeTiers sets the number of tiers calculated by PickFault, default 1.
+func ProbeTiers(n int) FaultProbe {
+	return func(p *faultProbes) {
+		p.tiers = n
+	}
+}
+
+// ProbeVoltage captures the voltages of the buses or equipment with the provided handles.
+func ProbeVoltage(hnds ...int) FaultProbe {
+	return func(p *faultProbes) {
+		p.buses = append(p.buses, hnds...)
+	}
+}
+
+// ProbeCurrent captures the currents of the branches or equipment with the provided handles.
+func ProbeCurrent(hnds ...int) FaultProbe {
+	return func(p *faultProbes) {
+		p.currents = append(p.currents, hnds...)
+	}
+}
+
+// ProbeAround captures the voltages of all buses, and the currents of all branches, within the provided number
+// of tiers of the bus. Branch currents are captured at the end nearest the bus.
+func ProbeAround(busHnd, tiers int) FaultProbe {
+	return func(p *faultProbes) {
+		p.around = append(p.around, struct{ bus, tiers int }{busHnd, tiers})
+	}
+}
+
+// FaultResult represents an immutable snapshot of a fault simulation result, see Client.SnapshotFault. It remains
+// valid after other faults are picked or simulated, and is safe for concurrent use.
+type FaultResult struct {
+	index       int
+	description string
+	total       SeqPhase
+	voltages    map[int]SeqPhase
+	currents    map[int]SeqPhase
+}
+
+// Index returns the fault index.
+func (r *FaultResult) Index() int {
+	return r.index
+}
+
+// Description returns the fault description.
+func (r *FaultResult) Description() string {
+	return r.description
+}
+
+// Total returns the total fault current.
+func (r *FaultResult) Total() SeqPhase {
+	return r.total
+}
+
+// Voltage returns the captured voltage for the bus or equipment handle.
+func (r *FaultResult) Voltage(hnd int) (SeqPhase, bool) {
+	v, ok := r.voltages[hnd]
+	return v, ok
+}
+
+// Current returns the captured current for the branch or equipment handle.
+func (r *FaultResult) Current(hnd int) (SeqPhase, bool) {
+	i, ok := r.currents[hnd]
+	return i, ok
+}
+
+// sortedKeys returns the sorted map keys.
+func sortedKeys(m map[int]SeqPhase) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+// VoltageHnds returns the sorted handles with captured voltages.
+func (r *FaultResult) VoltageHnds() []int {
+	return sortedKeys(r.voltages)
+}
+
+// CurrentHnds returns the sorted handles with captured currents.
+func (r *FaultResult) CurrentHnds() []int {
+	return sortedKeys(r.currents)
+}
+
+// Equal reports whether the results capture the same quantities with values equal within the absolute tolerance.
+// Indexes and descriptions are not compared.
+func (r *FaultResult) Equal(o *FaultResult, tol float64) bool {
+	if !r.total.equal(o.total, tol) || len(r.voltages) != len(o.voltages) || len(r.currents) != len(o.currents) {
+		return false
+	}
+	for hnd, v := range r.voltages {
+		if ov, ok := o.voltages[hnd]; !ok || !v.equal(ov, tol) {
+			return false
+		}
+	}
+	for hnd, i := range r.currents {
+		if oi, ok := o.currents[hnd]; !ok || !i.equal(oi, tol) {
+			return false
+		}
+	}
+	return true
+}
+
+// faultResultJSON represents the JSON encoding of a FaultResult.
+type faultResultJSON struct {
+	Index       int              `json:"index"`
+	Description string           `json:"description"`
+	Total       SeqPhase         `json:"total"`
+	Voltages    map[int]SeqPhase `json:"voltages,omitempty"`
+	Currents    map[int]SeqPhase `json:"currents,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (r *FaultResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(faultResultJSON{r.index, r.description, r.total, r.voltages, r.currents})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (r *FaultResult) UnmarshalJSON(b []byte) error {
+	var v faultResultJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*r = FaultResult{index: v.Index, description: v.Description, total: v.Total, voltages: v.Voltages, currents: v.Currents}
+	return nil
+}
+
+// scVoltage returns the short circuit voltage in sequence and phase form.
+func (c *Client) scVoltage(hnd int) (SeqPhase, error) {
+	var v SeqPhase
+	var err error
+	if v.Seq[0], v.Seq[1], v.Seq[2], err = c.GetSCVoltageSeq(hnd); err != nil {
+		return v, err
+	}
+	v.Phase[0], v.Phase[1], v.Phase[2], err = c.GetSCVoltagePhase(hnd)
+	return v, err
+}
+
+// scCurrent returns the short circuit current in sequence and phase form.
+func (c *Client) scCurrent(hnd int) (SeqPhase, error) {
+	var i SeqPhase
+	var err error
+	if i.Seq[0], i.Seq[1], i.Seq[2], err = c.GetSCCurrentSeq(hnd); err != nil {
+		return i, err
+	}
+	i.Phase[0], i.Phase[1], i.Phase[2], err = c.GetSCCurrentPhase(hnd)
+	return i, err
+}
+
+// busesAround returns the buses within the provided number of tiers of the bus, and the branches from each
+// bus to the next tier.
+func (c *Client) busesAround(busHnd, tiers int) (buses, branches []int) {
+	seen := map[int]bool{busHnd: true}
+	buses = []int{busHnd}
+	frontier := []int{busHnd}
+	for t := 0; t < tiers; t++ {
+		var next []int
+		for _, b := range frontier {
+			for bi := c.NextBusEquipment(b, TCBranch); bi.Next(); {
+				branches = append(branches, bi.Hnd())
+				var bus2Hnd int
+				if err := c.GetData(bi.Hnd(), BRnBus2Hnd).Scan(&bus2Hnd); err != nil || seen[bus2Hnd] {
+					continue
+				}
+				seen[bus2Hnd] = true
+				buses = append(buses, bus2Hnd)
+				next = append(next, bus2Hnd)
+			}
+		}
+		frontier = next
+	}
+	return buses, branches
+}
+
+// SnapshotFault picks the fault with the provided index, 1 for the first fault, and captures the fault
+// description, total fault current and the quantities requested by the probes into a FaultResult. DoFault must
+// be called first.
+func (c *Client) SnapshotFault(index int, probes ...FaultProbe) (*FaultResult, error) {
+	if index < 1 {
+		return nil, fmt.Errorf("SnapshotFault: fault index must be positive, got %d", index)
+	}
+	p := faultProbes{tiers: 1}
+	for _, probe := range probes {
+		probe(&p)
+	}
+	buses, currents := p.buses, p.currents
+	for _, a := range p.around {
+		b, br := c.busesAround(a.bus, a.tiers)
+		buses = append(buses, b...)
+		currents = append(currents, br...)
+	}
+
+	if err := c.PickFault(index, p.tiers); err != nil {
+		return nil, fmt.Errorf("SnapshotFault: %v", err)
+	}
+	r := &FaultResult{
+		index:       index,
+		description: c.FaultDescription(index),
+		voltages:    make(map[int]SeqPhase, len(buses)),
+		currents:    make(map[int]SeqPhase, len(currents)),
+	}
+	var err error
+	if r.total, err = c.scCurrent(HNDSC); err != nil {
+		return nil, fmt.Errorf("SnapshotFault: total current: %v", err)
+	}
+	for _, hnd := range buses {
+		if _, ok := r.voltages[hnd]; ok {
+			continue
+		}
+		if r.voltages[hnd], err = c.scVoltage(hnd); err != nil {
+			return nil, fmt.Errorf("SnapshotFault: voltage %s: %v", c.printID(hnd), err)
+		}
+	}
+	for _, hnd := range currents {
+		if _, ok := r.currents[hnd]; ok {
+			continue
+		}
+		if r.currents[hnd], err = c.scCurrent(hnd); err != nil {
+			return nil, fmt.Errorf("SnapshotFault: current %s: %v", c.printID(hnd), err)
+		}
+	}
+	return r, nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func testFaultResult() *FaultResult {
+	sp := func(mag float64) SeqPhase {
+		var s SeqPhase
+		s.Phase[0], s.Phase[1], s.Phase[2] = NewPhasor(mag, 0), NewPhasor(mag, -120), NewPhasor(mag, 120)
+		s.Seq[0], s.Seq[1], s.Seq[2] = PhaseToSeq(s.Phase[0], s.Phase[1], s.Phase[2])
+		return s
+	}
+	return &FaultResult{
+		index:       1,
+		description: "1. Bus Fault on: 6 NEVADA 132. kV 3LG",
+		total:       sp(5000),
+		voltages:    map[int]SeqPhase{20: sp(0), 10: sp(40)},
+		currents:    map[int]SeqPhase{30: sp(1200)},
+	}
+}
+
+func TestFaultResult_Accessors(t *testing.T) {
+	r := testFaultResult()
+	if r.Index() != 1 || r.Description() == "" {
+		t.Errorf("unexpected index or description %d %q", r.Index(), r.Description())
+	}
+	if got := r.Total().Seq[1].Mag(); !almostEqual(got, 5000) {
+		t.Errorf("expected positive sequence 5000, got %v", got)
+	}
+	if hnds := r.VoltageHnds(); len(hnds) != 2 || hnds[0] != 10 || hnds[1] != 20 {
+		t.Errorf("expected sorted voltage handles, got %v", hnds)
+	}
+	if _, ok := r.Current(30); !ok {
+		t.Error("expected current for handle 30")
+	}
+	if _, ok := r.Voltage(30); ok {
+		t.Error("unexpected voltage for handle 30")
+	}
+}
+
+func TestFaultResult_JSON(t *testing.T) {
+	r := testFaultResult()
+	b, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got FaultResult
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Index() != r.Index() || got.Description() != r.Description() {
+		t.Errorf("expected %d %q, got %d %q", r.Index(), r.Description(), got.Index(), got.Description())
+	}
+	if !got.Equal(r, 1e-6) {
+		t.Errorf("expected round trip equal, got %s", b)
+	}
+}
+
+func TestFaultResult_Equal(t *testing.T) {
+	a, b := testFaultResult(), testFaultResult()
+	if !a.Equal(b, 0) {
+		t.Error("expected equal results")
+	}
+	v := b.voltages[10]
+	v.Phase[0] += 0.5
+	b.voltages[10] = v
+	if a.Equal(b, 0.1) {
+		t.Error("expected results to differ")
+	}
+	if !a.Equal(b, 1) {
+		t.Error("expected results equal within tolerance")
+	}
+	delete(b.currents, 30)
+	if a.Equal(b, 1) {
+		t.Error("expected results with different probes to differ")
+	}
+}
+
+func TestClient_SnapshotFault(t *testing.T) {
+	c := NewClient()
+	defer c.Release()
+	if err := c.LoadDataFile(testCase); err != nil {
+		t.Fatal(err)
+	}
+	busHnd, err := c.FindBusByName("NEVADA", 132)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DoFault(busHnd, NewFaultConfig(FaultCloseIn(), FaultConn(ABC, AG))); err != nil {
+		t.Fatal(err)
+	}
+	r1, err := c.SnapshotFault(1, ProbeAround(busHnd, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r2, err := c.SnapshotFault(2, ProbeAround(busHnd, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r1.Equal(r2, 1e-3) {
+		t.Error("expected 3LG and 1LG results to differ")
+	}
+	if v, ok := r1.Voltage(busHnd); !ok || v.Seq[1].Mag() > 1e-3 {
+		t.Errorf("expected zero faulted bus voltage, got %v", v)
+	}
+	if len(r1.CurrentHnds()) == 0 {
+		t.Error("expected branch currents")
+	}
+	if _, err := c.SnapshotFault(0); err == nil {
+		t.Error("expected error for invalid index")
+	}
+}
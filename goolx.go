@@ -33,7 +33,11 @@ const (
 // Client represents a new goolx api client. OlxAPI calls cannot be called in parallel,
 // the underlying dll procedure calls share memory and do not support cuncurency.
 type Client struct {
-	olxAPI *olxapi.OlxAPI
+	olxAPI    *olxapi.OlxAPI
+	nameIndex *NameIndex // Cached name index, see NameIndex.
+
+	// Name index data set and not yet posted, the index is invalidated again on PostData.
+	nameIndexPending bool
 }
 
 // NewClient returns a new goolx Client instance.
@@ -79,11 +83,13 @@ func (c *Client) SaveDataFile(name string) error {
 
 // LoadDataFile loads *.olr file from disk. Opens read/write.
 func (c *Client) LoadDataFile(name string) error {
+	c.InvalidateNameIndex()
 	return c.olxAPI.LoadDataFile(name, false)
 }
 
 // LoadDataFile loads *.olr file from disk. Opens read only.
 func (c *Client) LoadDataFileReadOnly(name string) error {
+	c.InvalidateNameIndex()
 	return c.olxAPI.LoadDataFile(name, true)
 }
 
@@ -94,16 +100,19 @@ func (c *Client) GetOlrFilename() string {
 
 // CloseDataFile closes the currently loaded *.olr data file.
 func (c *Client) CloseDataFile() error {
+	c.InvalidateNameIndex()
 	return c.olxAPI.CloseDataFile()
 }
 
 // ReadChangeFile reads *.chf file from disk and applies to case
 func (c *Client) ReadChangeFile(name string) error {
+	c.InvalidateNameIndex()
 	return c.olxAPI.ReadChangeFile(name)
 }
 
 // DeleteEquipment deletes the equipment with the provided handle.
 func (c *Client) DeleteEquipment(hnd int) error {
+	c.InvalidateNameIndex()
 	return c.olxAPI.DeleteEquipment(hnd)
 }
 
@@ -430,11 +439,20 @@ func (c *Client) SetData(hnd, token int, data interface{}) error {
 	default:
 		return fmt.Errorf("SetData: data type %T not supported", data)
 	}
+	if eqType, err := c.EquipmentType(hnd); err == nil && isNameIndexToken(eqType, token) {
+		c.InvalidateNameIndex()
+		c.nameIndexPending = true
+	}
 	return nil
 }
 
 // PostData will post data for the provided equipment handle that was previously set using the SetData method.
+// The name index is invalidated if bus names, nominal kV, branch buses or circuit ids were set.
 func (c *Client) PostData(hnd int) error {
+	if c.nameIndexPending {
+		c.InvalidateNameIndex()
+		c.nameIndexPending = false
+	}
 	return c.olxAPI.PostData(hnd)
 }
 
@@ -622,7 +640,8 @@ func (c *Client) TagsSet(hnd int, tags ...string) error {
 	return nil
 }
 
-// TagsAppend appends the provided tags to the object tag string. Does not check for duplicate tags
+// TagsAppend appends the provided tags to the object tag string. Does not check for duplicate tags, see LoadTagSet
+// for deduplicated tag modification.
 func (c *Client) TagsAppend(hnd int, newTags ...string) error {
 	tags, err := c.TagsGet(hnd)
 	if err != nil {
@@ -706,7 +725,8 @@ type Journal struct {
 	ModifiedBy string
 }
 
-// GetJournal returns the object journal record for the provided handle.
+// GetJournal returns the object journal record for the provided handle. An empty Journal is returned if the
+// record cannot be split, see GetJournalRecord for parsed timestamps and explicit errors.
 func (c *Client) GetJournal(hnd int) Journal {
 	s := c.olxAPI.GetObjJournalRecord(hnd)
 	ss := strings.Split(s, "\n")
@@ -740,8 +760,11 @@ func (c *Client) GetUDFByIndex(hnd, i int) (field, value string, err error) {
 
 // SetUDF sets the user defined field at the provided equipment with the specified field name and value.
 // SetUDF does not create a new user defined field if it does not exist. User defined fields must be created
-// in Oneliner GUI.
+// in Oneliner GUI. Returns a UDFLimitError if the field name or value exceeds the size limits, see ValidateUDF.
 func (c *Client) SetUDF(hnd int, field, value string) error {
+	if err := ValidateUDF(field, value); err != nil {
+		return err
+	}
 	err := c.olxAPI.SetObjUDF(hnd, field, value)
 	if err != nil {
 		return err
@@ -822,7 +845,7 @@ func (c *Client) GetPSCVoltagePU(hnd int) ([]Phasor, error) {
 }
 
 // GetSCVoltagePhase gets the short circuit phase voltage for the equipment with the provided handle.
-// Returns Va, Vb, Vc Phasor types. PickFault must be called first.
+// Returns Va, Vb, Vc Phasor types at the first terminal, see GetSCVoltageTerminals. PickFault must be called first.
 func (c *Client) GetSCVoltagePhase(hnd int) (Va, Vb, Vc Phasor, err error) {
 	vdOut1, vdOut2, err := c.olxAPI.GetSCVoltage(hnd, 3)
 	if err != nil {
@@ -835,7 +858,7 @@ func (c *Client) GetSCVoltagePhase(hnd int) (Va, Vb, Vc Phasor, err error) {
 }
 
 // GetSCVoltageSeq gets the short circuit sequence voltagse for the equipment with the provided handle.
-// Returns V0, V1, V2 Phasor types.
+// Returns V0, V1, V2 Phasor types at the first terminal, see GetSCVoltageTerminals.
 func (c *Client) GetSCVoltageSeq(hnd int) (V0, V1, V2 Phasor, err error) {
 	vdOut1, vdOut2, err := c.olxAPI.GetSCVoltage(hnd, 1)
 	if err != nil {
@@ -848,7 +871,7 @@ func (c *Client) GetSCVoltageSeq(hnd int) (V0, V1, V2 Phasor, err error) {
 }
 
 // GetSCCurrentPhase gets the short circuit phase current for the equipment with the provided handle.
-// Returns Ia, Ib, Ic Phasor types. PickFault must be called first.
+// Returns Ia, Ib, Ic Phasor types at the first terminal, see GetSCCurrentTerminals. PickFault must be called first.
 func (c *Client) GetSCCurrentPhase(hnd int) (Ia, Ib, Ic Phasor, err error) {
 	vdOut1, vdOut2, err := c.olxAPI.GetSCCurrent(hnd, 3)
 	if err != nil {
@@ -861,7 +884,7 @@ func (c *Client) GetSCCurrentPhase(hnd int) (Ia, Ib, Ic Phasor, err error) {
 }
 
 // GetSCCurrentSeq gets the short circuit sequence current for the equipment with the provided handle.
-// Returns I0, I1, I2 Phasor types. PickFault must be called first.
+// Returns I0, I1, I2 Phasor types at the first terminal, see GetSCCurrentTerminals. PickFault must be called first.
 func (c *Client) GetSCCurrentSeq(hnd int) (I0, I1, I2 Phasor, err error) {
 	vdOut1, vdOut2, err := c.olxAPI.GetSCCurrent(hnd, 1)
 	if err != nil {
@@ -0,0 +1,228 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+// Package iec60909 provides short circuit current calculations per IEC 60909-0:2016, using the equivalent source
+// at the short circuit location and the bolted fault results and X/R ratios from Oneliner.
+package iec60909
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+)
+
+// Case represents the IEC 60909 maximum or minimum short circuit current case.
+type Case int
+
+// Short circuit current cases.
+const (
+	CMax Case = iota // Maximum short circuit currents, equipment rating.
+	CMin             // Minimum short circuit currents, protection sensitivity.
+)
+
+func (c Case) String() string {
+	switch c {
+	case CMax:
+		return "cmax"
+	case CMin:
+		return "cmin"
+	}
+	return fmt.Sprintf("Case(%d)", int(c))
+}
+
+// VoltageFactor returns the voltage factor c of Table 1 for the nominal system voltage in kV. For low voltage
+// systems, tol10 selects systems with a +10% voltage tolerance, otherwise +6%.
+func VoltageFactor(kv float64, c Case, tol10 bool) float64 {
+	if kv > 1 {
+		if c == CMin {
+			return 1.00
+		}
+		return 1.10
+	}
+	switch {
+	case c == CMax && tol10:
+		return 1.10
+	case c == CMax:
+		return 1.05
+	case tol10:
+		return 0.90
+	}
+	return 0.95
+}
+
+// PeakMethod represents the method used to determine the peak short circuit current factor κ.
+type PeakMethod int
+
+// Peak current methods. Method A, the smallest R/X ratio of all network branches, and method C, the equivalent
+// frequency, require branch and 20 Hz network data and are not supported.
+const (
+	MethodB  PeakMethod = iota // Ratio at the short circuit location, 1.15κ limited to 1.8 for low voltage and 2.0.
+	MethodZk                   // κ from the R/X ratio of the short circuit impedance Zk, not an IEC 60909 method.
+)
+
+func (m PeakMethod) String() string {
+	switch m {
+	case MethodB:
+		return "B"
+	case MethodZk:
+		return "Zk"
+	}
+	return fmt.Sprintf("PeakMethod(%d)", int(m))
+}
+
+// Kappa returns the peak current factor κ = 1.02 + 0.98e^(-3R/X) for the X/R ratio. An infinite X/R ratio
+// returns 2.
+func Kappa(xr float64) float64 {
+	if math.IsInf(xr, 1) || xr <= 0 {
+		return 2
+	}
+	return 1.02 + 0.98*math.Exp(-3/xr)
+}
+
+// PeakFactor returns the peak current factor κ for the X/R ratio at the short circuit location using the provided
+// method, at the nominal system voltage in kV.
+func PeakFactor(xr, kv float64, m PeakMethod) float64 {
+	k := Kappa(xr)
+	if m != MethodB {
+		return k
+	}
+	limit := 2.0
+	if kv <= 1 {
+		limit = 1.8
+	}
+	return math.Min(1.15*k, limit)
+}
+
+// Peak returns the peak short circuit current ip = κ√2Ik".
+func Peak(ikpp, kappa float64) float64 {
+	return kappa * math.Sqrt2 * ikpp
+}
+
+// muCoeffs are the factor μ coefficients for minimum time delays of 0.02, 0.05, 0.10 and 0.25 s.
+var muCoeffs = [...]struct{ tmin, a, b, e float64 }{
+	{0.02, 0.84, 0.26, 0.26},
+	{0.05, 0.71, 0.51, 0.30},
+	{0.10, 0.62, 0.72, 0.32},
+	{0.25, 0.56, 0.94, 0.38},
+}
+
+// Mu returns the symmetrical breaking current factor μ for near to generator short circuits, where ratio is the
+// generator or motor partial short circuit current relative to its rated current, IkG"/IrG, and tmin is the minimum
+// time delay in seconds. Time delays between the tabulated values are linearly interpolated. Far from generator
+// short circuits, ratio at or below 2, return 1.
+func Mu(ratio, tmin float64) float64 {
+	if ratio <= 2 {
+		return 1
+	}
+	mu := func(i int) float64 {
+		c := muCoeffs[i]
+		return math.Min(1, c.a+c.b*math.Exp(-c.e*ratio))
+	}
+	if tmin <= muCoeffs[0].tmin {
+		return mu(0)
+	}
+	for i := 1; i < len(muCoeffs); i++ {
+		if tmin <= muCoeffs[i].tmin {
+			t0, t1 := muCoeffs[i-1].tmin, muCoeffs[i].tmin
+			return mu(i-1) + (mu(i)-mu(i-1))*(tmin-t0)/(t1-t0)
+		}
+	}
+	return mu(len(muCoeffs) - 1)
+}
+
+// ThermalM returns the factor m for the heat effect of the DC component, for the peak current factor κ, the system
+// frequency in Hz and the short circuit duration Tk in seconds.
+func ThermalM(kappa, f, tk float64) float64 {
+	l := math.Log(kappa - 1)
+	if l >= 0 {
+		// Undamped DC component.
+		return 2
+	}
+	return (math.Exp(4*f*tk*l) - 1) / (2 * f * tk * l)
+}
+
+// Thermal returns the thermal equivalent short circuit current Ith = Ik"√(m+n). The factor n for the heat effect
+// of the AC component is 1 for far from generator short circuits.
+func Thermal(ikpp, m, n float64) float64 {
+	return ikpp * math.Sqrt(m+n)
+}
+
+// NetworkFeeder represents a network feeder connected at a point of common coupling.
+type NetworkFeeder struct {
+	KV   float64 // Nominal system voltage UnQ at the feeder connection point in kV.
+	Ikpp float64 // Initial symmetrical short circuit current I"kQ at the feeder connection point in kA.
+	RX   float64 // Ratio RQ/XQ, default 0.1 where not known.
+}
+
+// Impedance returns the network feeder positive sequence impedance ZQ = cUnQ/(√3I"kQ) in ohms, referred to the
+// feeder connection point.
+func (n NetworkFeeder) Impedance(c float64) complex128 {
+	rx := n.RX
+	if rx == 0 {
+		rx = 0.1
+	}
+	z := c * n.KV / (math.Sqrt(3) * n.Ikpp)
+	x := z / math.Sqrt(1+rx*rx)
+	return complex(rx*x, x)
+}
+
+// ImpedanceAt returns the network feeder impedance referred to the low voltage side of a transformer with the
+// rated transformation ratio tr.
+func (n NetworkFeeder) ImpedanceAt(c, tr float64) complex128 {
+	return n.Impedance(c) / complex(tr*tr, 0)
+}
+
+// Feeder represents a network feeder supplying a bus through a network transformer. The corrected feeder and
+// transformer impedances replace the short circuit impedances of the bus equivalent, see Study.Feeders.
+type Feeder struct {
+	NetworkFeeder
+	Ratio float64    // Rated transformation ratio of the network transformer, 1 where the feeder connects directly.
+	ZT    complex128 // Transformer positive sequence impedance in ohms, referred to the bus side.
+	ZT0   complex128 // Transformer zero sequence impedance in ohms, referred to the bus side.
+	XT    float64    // Transformer relative reactance in per unit of its rating.
+}
+
+// Impedance returns the positive and zero sequence short circuit impedances at the bus in ohms, for the voltage
+// factor c of the case at the feeder and the maximum voltage factor cmax at the bus. The transformer impedances are
+// corrected by KT, see TransformerCorrection. The feeder zero sequence impedance is not seen through the transformer,
+// a zero ZT0 returns an infinite zero sequence impedance.
+func (f Feeder) Impedance(c, cmax float64) (z1, z0 complex128) {
+	ratio := f.Ratio
+	if ratio == 0 {
+		ratio = 1
+	}
+	kt := complex(1, 0)
+	if f.ZT != 0 {
+		kt = complex(TransformerCorrection(cmax, f.XT), 0)
+	}
+	z1 = f.ImpedanceAt(c, ratio) + kt*f.ZT
+	z0 = kt * f.ZT0
+	if z0 == 0 {
+		z0 = cmplx.Inf()
+	}
+	return z1, z0
+}
+
+// TransformerCorrection returns the impedance correction factor KT = 0.95cmax/(1+0.6xT) for network transformers,
+// where xT is the relative reactance of the transformer in per unit of its rating.
+func TransformerCorrection(cmax, xT float64) float64 {
+	return 0.95 * cmax / (1 + 0.6*xT)
+}
+
+// xr returns the X/R ratio of the impedance, infinite for a purely reactive impedance.
+func xr(z complex128) float64 {
+	if real(z) == 0 {
+		return math.Inf(1)
+	}
+	return imag(z) / real(z)
+}
+
+// initial returns the initial symmetrical short circuit current in kA, for the voltage factor c, the nominal
+// system voltage in kV and the fault loop impedance in ohms, scaled by k for the fault type.
+func initial(c, kv, k float64, z complex128) float64 {
+	if cmplx.IsInf(z) || z == 0 {
+		return 0
+	}
+	return k * c * kv / (math.Sqrt(3) * cmplx.Abs(z))
+}
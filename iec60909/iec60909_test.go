@@ -0,0 +1,115 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package iec60909
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+// near reports whether a and b are equal within the relative tolerance.
+func near(a, b, tol float64) bool {
+	return math.Abs(a-b) <= tol*math.Max(math.Abs(a), math.Abs(b))
+}
+
+func TestVoltageFactor(t *testing.T) {
+	tests := []struct {
+		kv    float64
+		c     Case
+		tol10 bool
+		want  float64
+	}{
+		{0.4, CMax, false, 1.05},
+		{0.4, CMax, true, 1.10},
+		{0.4, CMin, false, 0.95},
+		{0.4, CMin, true, 0.90},
+		{20, CMax, false, 1.10},
+		{20, CMin, false, 1.00},
+		{132, CMax, true, 1.10},
+	}
+	for _, tt := range tests {
+		if got := VoltageFactor(tt.kv, tt.c, tt.tol10); got != tt.want {
+			t.Errorf("%gkV %s tol10 %v: expected %v, got %v", tt.kv, tt.c, tt.tol10, tt.want, got)
+		}
+	}
+}
+
+func TestPeakFactor(t *testing.T) {
+	tests := []struct {
+		xr, kv float64
+		m      PeakMethod
+		want   float64
+	}{
+		{10, 20, MethodZk, 1.746002},
+		{3, 20, MethodZk, 1.380522},
+		{3, 20, MethodB, 1.587600},
+		{10, 0.4, MethodB, 1.8},
+		{10, 20, MethodB, 2.0},
+		{math.Inf(1), 20, MethodZk, 2.0},
+	}
+	for _, tt := range tests {
+		if got := PeakFactor(tt.xr, tt.kv, tt.m); !near(got, tt.want, 1e-6) {
+			t.Errorf("X/R %g %gkV method %s: expected %v, got %v", tt.xr, tt.kv, tt.m, tt.want, got)
+		}
+	}
+	if got := Peak(10, 1.8); !near(got, 25.455844, 1e-6) {
+		t.Errorf("expected ip 25.455844kA, got %v", got)
+	}
+}
+
+func TestMu(t *testing.T) {
+	tests := []struct {
+		ratio, tmin, want float64
+	}{
+		{1.5, 0.1, 1},
+		{5, 0.1, 0.765365},
+		{5, 0.075, 0.794581},
+		{5, 0.01, 0.84 + 0.26*math.Exp(-0.26*5)},
+		{5, 1, 0.56 + 0.94*math.Exp(-0.38*5)},
+		{2.1, 0.02, 0.990608},
+	}
+	for _, tt := range tests {
+		if got := Mu(tt.ratio, tt.tmin); !near(got, tt.want, 1e-6) {
+			t.Errorf("ratio %g tmin %g: expected %v, got %v", tt.ratio, tt.tmin, tt.want, got)
+		}
+	}
+}
+
+func TestThermal(t *testing.T) {
+	if got := ThermalM(1.8, 50, 1); !near(got, 0.044814, 1e-4) {
+		t.Errorf("expected m 0.044814, got %v", got)
+	}
+	if got := ThermalM(2, 50, 1); got != 2 {
+		t.Errorf("expected m 2 for undamped DC component, got %v", got)
+	}
+	if got := Thermal(10, 0.44, 1); !near(got, 12, 1e-9) {
+		t.Errorf("expected Ith 12kA, got %v", got)
+	}
+}
+
+func TestNetworkFeeder(t *testing.T) {
+	n := NetworkFeeder{KV: 20, Ikpp: 10}
+	z := n.Impedance(1.1)
+	if !near(cmplx.Abs(z), 1.270171, 1e-6) || !near(imag(z), 0.995*cmplx.Abs(z), 1e-3) || !near(real(z), 0.1*imag(z), 1e-9) {
+		t.Errorf("unexpected feeder impedance %v", z)
+	}
+	if got := n.ImpedanceAt(1.1, 50); !near(cmplx.Abs(got), 1.270171/2500, 1e-6) {
+		t.Errorf("unexpected referred feeder impedance %v", got)
+	}
+	if got := TransformerCorrection(1.05, 0.06); !near(got, 0.962838, 1e-6) {
+		t.Errorf("expected KT 0.962838, got %v", got)
+	}
+
+	// 20/0.4 kV network transformer, 6% reactance.
+	f := Feeder{NetworkFeeder: n, Ratio: 50, ZT: complex(0, 0.015), ZT0: complex(0, 0.015), XT: 0.06}
+	z1, z0 := f.Impedance(1.1, 1.05)
+	if cmplx.Abs(z1-complex(5.055468e-5, 0.01494811)) > 1e-8 || cmplx.Abs(z0-complex(0, 0.01444257)) > 1e-8 {
+		t.Errorf("unexpected feeder impedances %v %v", z1, z0)
+	}
+	if _, z0 := (Feeder{NetworkFeeder: n}).Impedance(1.1, 1.1); !cmplx.IsInf(z0) {
+		t.Errorf("expected infinite zero sequence impedance, got %v", z0)
+	}
+}
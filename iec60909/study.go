@@ -0,0 +1,163 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package iec60909
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/readpe/goolx"
+)
+
+// Study represents an IEC 60909 short circuit study configuration. Other than the network feeders, the network
+// changes required for the minimum case, e.g. excluding motors and conductor temperatures at the end of the short
+// circuit, must be made in the model.
+type Study struct {
+	Case   Case
+	Tol10  bool // Low voltage systems with a +10% voltage tolerance, see VoltageFactor.
+	Method PeakMethod
+
+	// Network feeders by bus handle. The feeder and corrected transformer impedances replace the short circuit
+	// impedances of the bus equivalent, and the X/R ratios are taken from the replaced impedances.
+	Feeders map[int]Feeder
+
+	Frequency float64 // System frequency in Hz, default 50.
+	Tk        float64 // Short circuit duration for the thermal equivalent current in seconds, default 1.
+
+	// Breaking current factor μ for the 3LG initial current in kA at the bus, see Mu. If nil, short circuits are
+	// far from generator and the breaking current is the initial current.
+	Mu func(busHnd int, ikpp float64) float64
+}
+
+// Result represents the IEC 60909 short circuit currents for a single fault type at a bus. Currents are in kA. Where
+// there is no short circuit current, e.g. a 1LG fault without a zero sequence path, all factors and currents are
+// zero.
+type Result struct {
+	BusHnd int
+	Name   string
+	KV     float64 // Nominal system voltage.
+	Fault  string  // Fault type, 3LG, LL or 1LG.
+
+	C     float64 // Voltage factor.
+	XR    float64 // X/R ratio at the short circuit location.
+	Kappa float64 // Peak current factor.
+
+	Ikpp float64 // Initial symmetrical short circuit current Ik".
+	Ip   float64 // Peak short circuit current.
+	Ib   float64 // Symmetrical short circuit breaking current.
+	Ith  float64 // Thermal equivalent short circuit current.
+}
+
+func (r Result) String() string {
+	return fmt.Sprintf("%s %0.2fkV %s Ik\":%0.3fkA ip:%0.3fkA", r.Name, r.KV, r.Fault, r.Ikpp, r.Ip)
+}
+
+// Run returns the 3LG, LL and 1LG short circuit currents at each bus, three results per bus. The short circuit
+// impedances are the Thevenin equivalents at the bus, see goolx.Client.Thevenin, or the network feeder impedances
+// where provided, with the equivalent voltage source cUn/√3 at the bus nominal voltage. The X/R ratios reported by
+// Oneliner are used for the 3LG and 1LG peak currents where available, otherwise the ratio of the short circuit
+// impedance. Previous fault results are cleared.
+func (s Study) Run(c *goolx.Client, busHnds ...int) (Table, error) {
+	table := make(Table, 0, 3*len(busHnds))
+	for _, hnd := range busHnds {
+		th, err := c.Thevenin(hnd)
+		if err != nil {
+			return nil, fmt.Errorf("Run: %v", err)
+		}
+		table = append(table, s.evaluate(th)...)
+	}
+	return table, nil
+}
+
+// evaluate returns the 3LG, LL and 1LG short circuit currents for the Thevenin equivalent.
+func (s Study) evaluate(th *goolx.Thevenin) []Result {
+	if s.Frequency == 0 {
+		s.Frequency = 50
+	}
+	if s.Tk == 0 {
+		s.Tk = 1
+	}
+	cf := VoltageFactor(th.KV, s.Case, s.Tol10)
+	z1, z2, z0 := th.Z1Ohms(), th.Z2Ohms(), th.Z0Ohms()
+	xr3, xr1 := th.XR3LG, th.XR1LG
+	if fd, ok := s.Feeders[th.BusHnd]; ok {
+		cq := VoltageFactor(fd.KV, s.Case, s.Tol10)
+		z1, z0 = fd.Impedance(cq, VoltageFactor(th.KV, CMax, s.Tol10))
+		z2 = z1
+		xr3, xr1 = 0, 0
+	}
+	faults := []struct {
+		name string
+		k    float64
+		z    complex128
+		xr   float64 // Reported X/R ratio.
+	}{
+		{"3LG", 1, z1, xr3},
+		{"LL", math.Sqrt(3), z1 + z2, 0},
+		{"1LG", 3, z1 + z2 + z0, xr1},
+	}
+	out := make([]Result, 0, len(faults))
+	for _, f := range faults {
+		r := Result{BusHnd: th.BusHnd, Name: th.Name, KV: th.KV, Fault: f.name, C: cf}
+		r.Ikpp = initial(cf, th.KV, f.k, f.z)
+		if r.Ikpp == 0 {
+			// No short circuit current, e.g. no zero sequence path for 1LG faults.
+			out = append(out, r)
+			continue
+		}
+		if r.XR = f.xr; r.XR <= 0 {
+			r.XR = xr(f.z)
+		}
+		r.Kappa = PeakFactor(r.XR, th.KV, s.Method)
+		r.Ip = Peak(r.Ikpp, r.Kappa)
+		r.Ib = r.Ikpp
+		if s.Mu != nil && f.name == "3LG" {
+			r.Ib *= s.Mu(th.BusHnd, r.Ikpp)
+		}
+		r.Ith = Thermal(r.Ikpp, ThermalM(r.Kappa, s.Frequency, s.Tk), 1)
+		out = append(out, r)
+	}
+	return out
+}
+
+// Table represents IEC 60909 short circuit results, see Study.Run.
+type Table []Result
+
+// tableHeader is the results table header row.
+var tableHeader = []string{
+	"Bus", "Un (kV)", "Fault", "c", "X/R", "κ", "Ik\" (kA)", "ip (kA)", "Ib (kA)", "Ith (kA)",
+}
+
+// Rows returns the results table rows, including the header, formatted for output. The X/R and κ cells are blank
+// where there is no short circuit current.
+func (t Table) Rows() [][]string {
+	f := func(v float64, prec int) string {
+		return strconv.FormatFloat(v, 'f', prec, 64)
+	}
+	rows := [][]string{tableHeader}
+	for _, r := range t {
+		xr, kappa := f(r.XR, 2), f(r.Kappa, 3)
+		if r.Ikpp == 0 {
+			xr, kappa = "", ""
+		}
+		rows = append(rows, []string{
+			r.Name, f(r.KV, 2), r.Fault, f(r.C, 2), xr, kappa,
+			f(r.Ikpp, 3), f(r.Ip, 3), f(r.Ib, 3), f(r.Ith, 3),
+		})
+	}
+	return rows
+}
+
+// WriteCSV writes the results table in CSV format.
+func (t Table) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(t.Rows()); err != nil {
+		return fmt.Errorf("WriteCSV: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package iec60909
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/readpe/goolx"
+)
+
+var testCase = `C:\Program Files (x86)\ASPEN\1LPFv15\SAMPLE09.OLR`
+
+func TestStudy_Evaluate(t *testing.T) {
+	th := &goolx.Thevenin{
+		BusHnd: 1,
+		Name:   "NEVADA",
+		KV:     132,
+		Base:   goolx.Base{MVA: 100, KV: 132},
+		Z1:     complex(0.01, 0.1),
+		Z2:     complex(0.01, 0.1),
+		Z0:     complex(0.03, 0.3),
+	}
+	s := Study{Method: MethodZk, Mu: func(busHnd int, ikpp float64) float64 { return 0.9 }}
+	results := s.evaluate(th)
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	tests := []struct {
+		fault             string
+		ikpp, ip, ib, ith float64
+	}{
+		{"3LG", 4.787375, 11.821080, 0.9 * 4.787375, 4.868378},
+		{"LL", 4.145988, 0, 4.145988, 0},
+		{"1LG", 2.872425, 0, 2.872425, 0},
+	}
+	for i, tt := range tests {
+		r := results[i]
+		if r.Fault != tt.fault || r.C != 1.1 || !near(r.XR, 10, 1e-9) {
+			t.Errorf("unexpected result %v", r)
+		}
+		if !near(r.Ikpp, tt.ikpp, 1e-6) || !near(r.Ib, tt.ib, 1e-6) {
+			t.Errorf("%s: expected Ik\" %v Ib %v, got %v %v", tt.fault, tt.ikpp, tt.ib, r.Ikpp, r.Ib)
+		}
+		if tt.ip != 0 && (!near(r.Ip, tt.ip, 1e-6) || !near(r.Ith, tt.ith, 1e-6)) {
+			t.Errorf("%s: expected ip %v Ith %v, got %v %v", tt.fault, tt.ip, tt.ith, r.Ip, r.Ith)
+		}
+	}
+
+	// Reported X/R ratios take precedence, minimum case voltage factor.
+	th.XR3LG = 5
+	results = Study{Case: CMin, Method: MethodZk}.evaluate(th)
+	if r := results[0]; r.XR != 5 || r.C != 1 || !near(r.Ikpp, 4.787375/1.1, 1e-6) || r.Ib != r.Ikpp {
+		t.Errorf("unexpected result %v", r)
+	}
+
+	// Method B, 1.15κ at the reported X/R ratio.
+	results = Study{}.evaluate(th)
+	if r := results[0]; !near(r.Kappa, 1.791511, 1e-6) || !near(r.Ip, 12.129191, 1e-6) {
+		t.Errorf("unexpected result %v", r)
+	}
+}
+
+func TestStudy_Feeder(t *testing.T) {
+	th := &goolx.Thevenin{
+		BusHnd: 1,
+		Name:   "LV",
+		KV:     0.4,
+		Base:   goolx.Base{MVA: 100, KV: 0.4},
+		Z1:     complex(0.01, 0.1),
+		Z2:     complex(0.01, 0.1),
+		Z0:     complex(0.03, 0.3),
+		XR3LG:  10,
+	}
+	s := Study{Feeders: map[int]Feeder{1: {
+		NetworkFeeder: NetworkFeeder{KV: 20, Ikpp: 10},
+		Ratio:         50,
+		ZT:            complex(0, 0.015),
+		ZT0:           complex(0, 0.015),
+		XT:            0.06,
+	}}}
+	results := s.evaluate(th)
+	if r := results[0]; r.C != 1.05 || !near(r.Ikpp, 16.221827, 1e-6) || !near(r.XR, 295.682113, 1e-6) || r.Kappa != 1.8 {
+		t.Errorf("unexpected 3LG result %v", r)
+	}
+	if r := results[2]; !near(r.Ikpp, 16.406838, 1e-6) {
+		t.Errorf("unexpected 1LG result %v", r)
+	}
+}
+
+func TestStudy_FeederNoZeroSeq(t *testing.T) {
+	th := &goolx.Thevenin{BusHnd: 1, Name: "LV", KV: 0.4, Base: goolx.Base{MVA: 100, KV: 0.4}, Z1: complex(0.01, 0.1), Z2: complex(0.01, 0.1), Z0: complex(0.03, 0.3)}
+	s := Study{Feeders: map[int]Feeder{1: {NetworkFeeder: NetworkFeeder{KV: 20, Ikpp: 10}, Ratio: 50, ZT: complex(0, 0.015), XT: 0.06}}}
+	table := Table(s.evaluate(th))
+	if r := table[2]; r.Ikpp != 0 || r.XR != 0 || r.Kappa != 0 || r.Ip != 0 || r.Ith != 0 {
+		t.Errorf("expected zero 1LG result, got %+v", r)
+	}
+	row := table.Rows()[3]
+	for i, cell := range row {
+		if strings.Contains(cell, "NaN") || strings.Contains(cell, "Inf") {
+			t.Errorf("%s: unexpected cell %q", tableHeader[i], cell)
+		}
+	}
+	if row[4] != "" || row[5] != "" {
+		t.Errorf("expected blank X/R and κ, got %q %q", row[4], row[5])
+	}
+}
+
+func TestTable_WriteCSV(t *testing.T) {
+	th := &goolx.Thevenin{Name: "NEVADA", KV: 132, Base: goolx.Base{MVA: 100, KV: 132}, Z1: complex(0.01, 0.1), Z2: complex(0.01, 0.1), Z0: complex(0.03, 0.3)}
+	table := Table(Study{}.evaluate(th))
+	var buf bytes.Buffer
+	if err := table.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 4 || len(rows[0]) != len(tableHeader) {
+		t.Fatalf("expected header and 3 rows, got %v", rows)
+	}
+	if rows[1][2] != "3LG" || rows[1][6] != "4.787" {
+		t.Errorf("unexpected row %v", rows[1])
+	}
+}
+
+func TestClient_Study(t *testing.T) {
+	c := goolx.NewClient()
+	defer c.Release()
+	if err := c.LoadDataFile(testCase); err != nil {
+		t.Fatal(err)
+	}
+	busHnd, err := c.FindBusByName("NEVADA", 132)
+	if err != nil {
+		t.Fatal(err)
+	}
+	table, err := Study{}.Run(c, busHnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(table) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(table))
+	}
+	for _, r := range table {
+		if r.Ikpp <= 0 || r.Ip <= r.Ikpp || r.Ith < r.Ikpp {
+			t.Errorf("unexpected result %v", r)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// JournalTimeLayout is the layout of Oneliner journal record timestamps, e.g. 1986/1/1 00:00.
+const JournalTimeLayout = "2006/1/2 15:04"
+
+// journalUnknown is the journal record value used when a timestamp or user is not recorded.
+const journalUnknown = "Unknown"
+
+// ErrJournalEmpty is returned when an object has no journal record.
+var ErrJournalEmpty = errors.New("journal record is empty")
+
+// JournalRecord represents a parsed Oneliner object journal record. Timestamps which are recorded as
+// Unknown are left as the zero time.
+type JournalRecord struct {
+	Hnd        int
+	CreatedAt  time.Time
+	CreatedBy  string
+	ModifiedAt time.Time
+	ModifiedBy string
+}
+
+// ParseJournal parses the raw journal record string returned by OlxAPI. The record must contain exactly four
+// lines: created at, created by, modified at and modified by.
+func ParseJournal(s string) (JournalRecord, error) {
+	var j JournalRecord
+	s = strings.TrimRight(s, "\r\n")
+	if s == "" {
+		return j, ErrJournalEmpty
+	}
+	ss := strings.Split(s, "\n")
+	if len(ss) != 4 {
+		return j, fmt.Errorf("ParseJournal: expected 4 lines, got %d", len(ss))
+	}
+	for i := range ss {
+		ss[i] = strings.TrimSpace(ss[i])
+	}
+	var err error
+	if j.CreatedAt, err = parseJournalTime(ss[0]); err != nil {
+		return j, fmt.Errorf("ParseJournal: created at: %v", err)
+	}
+	if j.ModifiedAt, err = parseJournalTime(ss[2]); err != nil {
+		return j, fmt.Errorf("ParseJournal: modified at: %v", err)
+	}
+	j.CreatedBy = ss[1]
+	j.ModifiedBy = ss[3]
+	return j, nil
+}
+
+// parseJournalTime parses a journal timestamp, returns the zero time for Unknown timestamps.
+func parseJournalTime(s string) (time.Time, error) {
+	if s == "" || s == journalUnknown {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation(JournalTimeLayout, s, time.Local)
+}
+
+// GetJournalRecord returns the parsed journal record for the provided handle. Returns ErrJournalEmpty if the
+// object has no journal record, or an error if the record is malformed.
+func (c *Client) GetJournalRecord(hnd int) (JournalRecord, error) {
+	j, err := ParseJournal(c.olxAPI.GetObjJournalRecord(hnd))
+	if err != nil {
+		return j, err
+	}
+	j.Hnd = hnd
+	return j, nil
+}
+
+// JournalQuery represents filter criteria for QueryJournal. Zero value fields are not applied.
+type JournalQuery struct {
+	// Equipment types to search, defaults to DefaultJournalTypes if empty.
+	EqTypes []int
+
+	// Records created or modified strictly after/before the provided times.
+	CreatedAfter   time.Time
+	CreatedBefore  time.Time
+	ModifiedAfter  time.Time
+	ModifiedBefore time.Time
+
+	// Records created or modified by the provided user, case insensitive.
+	CreatedBy  string
+	ModifiedBy string
+}
+
+// DefaultJournalTypes are the equipment types searched by QueryJournal when no types are provided.
+var DefaultJournalTypes = []int{
+	TCBus, TCGen, TCLoad, TCShunt, TCSVD,
+	TCLine, TCXFMR, TCXFMR3, TCPS, TCSCAP, TCSwitch,
+	TCRLYGroup, TCRLYOCG, TCRLYOCP, TCRLYDSG, TCRLYDSP, TCFuse, TCRECLSRP, TCRECLSRG, TCRLYD, TCRLYV,
+	TCScheme, TCBreaker,
+}
+
+// Match reports whether the journal record satisfies the query criteria. EqTypes are not considered.
+// Records with unknown timestamps never match time criteria.
+func (q JournalQuery) Match(j JournalRecord) bool {
+	switch {
+	case !q.CreatedAfter.IsZero() && (j.CreatedAt.IsZero() || !j.CreatedAt.After(q.CreatedAfter)):
+		return false
+	case !q.CreatedBefore.IsZero() && (j.CreatedAt.IsZero() || !j.CreatedAt.Before(q.CreatedBefore)):
+		return false
+	case !q.ModifiedAfter.IsZero() && (j.ModifiedAt.IsZero() || !j.ModifiedAt.After(q.ModifiedAfter)):
+		return false
+	case !q.ModifiedBefore.IsZero() && (j.ModifiedAt.IsZero() || !j.ModifiedAt.Before(q.ModifiedBefore)):
+		return false
+	case q.CreatedBy != "" && !strings.EqualFold(q.CreatedBy, j.CreatedBy):
+		return false
+	case q.ModifiedBy != "" && !strings.EqualFold(q.ModifiedBy, j.ModifiedBy):
+		return false
+	}
+	return true
+}
+
+// QueryJournal searches the journal records of all equipment in the case, returning the records matching
+// the query. Equipment without a journal record are skipped, malformed records return an error.
+func (c *Client) QueryJournal(q JournalQuery) ([]JournalRecord, error) {
+	eqTypes := q.EqTypes
+	if len(eqTypes) == 0 {
+		eqTypes = DefaultJournalTypes
+	}
+	var records []JournalRecord
+	for _, eqType := range eqTypes {
+		for ei := c.NextEquipment(eqType); ei.Next(); {
+			j, err := c.GetJournalRecord(ei.Hnd())
+			if err == ErrJournalEmpty {
+				continue
+			}
+			if err != nil {
+				return records, fmt.Errorf("QueryJournal: %s: %v", c.printID(ei.Hnd()), err)
+			}
+			if q.Match(j) {
+				records = append(records, j)
+			}
+		}
+	}
+	return records, nil
+}
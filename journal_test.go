@@ -0,0 +1,104 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseJournal(t *testing.T) {
+	t.Run("Unknown", func(t *testing.T) {
+		j, err := ParseJournal("Unknown\nUnknown\n1986/1/1 00:00\nUnknown")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !j.CreatedAt.IsZero() {
+			t.Errorf("expected zero created at, got %v", j.CreatedAt)
+		}
+		expected := time.Date(1986, 1, 1, 0, 0, 0, 0, time.Local)
+		if !j.ModifiedAt.Equal(expected) {
+			t.Errorf("expected %v, got %v", expected, j.ModifiedAt)
+		}
+	})
+	t.Run("Users", func(t *testing.T) {
+		j, err := ParseJournal("2021/3/14 09:26\r\njdoe\r\n2021/12/1 17:05\r\nasmith\r\n")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if j.CreatedBy != "jdoe" || j.ModifiedBy != "asmith" {
+			t.Errorf("unexpected users %q %q", j.CreatedBy, j.ModifiedBy)
+		}
+		expected := time.Date(2021, 12, 1, 17, 5, 0, 0, time.Local)
+		if !j.ModifiedAt.Equal(expected) {
+			t.Errorf("expected %v, got %v", expected, j.ModifiedAt)
+		}
+	})
+	t.Run("Empty", func(t *testing.T) {
+		if _, err := ParseJournal(""); err != ErrJournalEmpty {
+			t.Errorf("expected %v, got %v", ErrJournalEmpty, err)
+		}
+	})
+	t.Run("Lines", func(t *testing.T) {
+		if _, err := ParseJournal("Unknown\nUnknown"); err == nil {
+			t.Errorf("expected line count error, got nil")
+		}
+	})
+	t.Run("Bad time", func(t *testing.T) {
+		if _, err := ParseJournal("yesterday\nUnknown\nUnknown\nUnknown"); err == nil {
+			t.Errorf("expected time parse error, got nil")
+		}
+	})
+}
+
+func TestJournalQuery_Match(t *testing.T) {
+	j := JournalRecord{
+		CreatedAt:  time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		CreatedBy:  "jdoe",
+		ModifiedAt: time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC),
+		ModifiedBy: "asmith",
+	}
+	tests := []struct {
+		name     string
+		q        JournalQuery
+		expected bool
+	}{
+		{name: "empty", q: JournalQuery{}, expected: true},
+		{name: "modified after", q: JournalQuery{ModifiedAfter: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}, expected: true},
+		{name: "modified after by", q: JournalQuery{ModifiedAfter: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), ModifiedBy: "ASMITH"}, expected: true},
+		{name: "modified by other", q: JournalQuery{ModifiedBy: "jdoe"}, expected: false},
+		{name: "created since", q: JournalQuery{CreatedAfter: time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)}, expected: false},
+		{name: "modified before", q: JournalQuery{ModifiedBefore: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}, expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.q.Match(j); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+	if (JournalQuery{CreatedAfter: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)}).Match(JournalRecord{}) {
+		t.Errorf("expected unknown timestamps not to match")
+	}
+}
+
+func TestClient_QueryJournal(t *testing.T) {
+	api := NewClient()
+	defer api.Release()
+
+	if err := api.LoadDataFile(testCase); err != nil {
+		t.Fatal(err)
+	}
+	records, err := api.QueryJournal(JournalQuery{
+		EqTypes:       []int{TCBus},
+		ModifiedAfter: time.Date(1985, 1, 1, 0, 0, 0, 0, time.Local),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) == 0 {
+		t.Errorf("expected modified bus records, got none")
+	}
+}
@@ -0,0 +1,338 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Physical constants used in line constant calculations.
+const (
+	mu0  = 4e-7 * math.Pi  // Permeability of free space, H/m.
+	eps0 = 8.854187817e-12 // Permittivity of free space, F/m.
+)
+
+// Conductor represents a single overhead conductor within a line geometry. Bundled phases are modeled using
+// multiple conductors with the same phase.
+type Conductor struct {
+	Phase  int     // Phase 1, 2 or 3 for phases A, B and C, 0 for grounded shield wires.
+	X      float64 // Horizontal position, m.
+	Y      float64 // Height above ground, m.
+	R      float64 // AC resistance, ohm/km.
+	GMR    float64 // Geometric mean radius, m.
+	Radius float64 // Outside radius, m.
+}
+
+// LineGeometry represents an overhead line tower geometry used to calculate line constants.
+type LineGeometry struct {
+	Conductors  []Conductor
+	Resistivity float64 // Earth resistivity, ohm-m.
+	Frequency   float64 // System frequency, Hz.
+}
+
+// LineConstants represents the calculated line constants per unit length. Sequence values assume a fully
+// transposed line.
+type LineConstants struct {
+	Zabc   [3][3]complex128 // Phase series impedance matrix, ohm/km.
+	Yabc   [3][3]complex128 // Phase shunt admittance matrix, S/km.
+	Z1, Z0 complex128       // Positive and zero sequence series impedance, ohm/km.
+	Y1, Y0 complex128       // Positive and zero sequence shunt admittance, S/km.
+}
+
+// validate checks the geometry for missing phases and non physical conductor data.
+func (g *LineGeometry) validate() error {
+	if g.Frequency <= 0 {
+		return fmt.Errorf("frequency must be positive")
+	}
+	if g.Resistivity <= 0 {
+		return fmt.Errorf("earth resistivity must be positive")
+	}
+	var phases [3]bool
+	for i, cd := range g.Conductors {
+		if cd.Phase < 0 || cd.Phase > 3 {
+			return fmt.Errorf("conductor %d: invalid phase %d", i, cd.Phase)
+		}
+		if cd.Phase > 0 {
+			phases[cd.Phase-1] = true
+		}
+		if cd.Y <= 0 || cd.GMR <= 0 || cd.Radius <= 0 || cd.R < 0 {
+			return fmt.Errorf("conductor %d: height, GMR and radius must be positive", i)
+		}
+		for j := range g.Conductors[:i] {
+			if g.Conductors[j].X == cd.X && g.Conductors[j].Y == cd.Y {
+				return fmt.Errorf("conductor %d: coincides with conductor %d", i, j)
+			}
+		}
+	}
+	for i, ok := range phases {
+		if !ok {
+			return fmt.Errorf("missing conductor for phase %d", i+1)
+		}
+	}
+	return nil
+}
+
+// Constants calculates the line constants of the geometry. Series impedances are calculated using Carson's
+// equations with the first order earth return correction, shunt admittances using Maxwell's potential
+// coefficients with conductor images. Shield wires and bundled conductors are eliminated using Kron reduction.
+func (g *LineGeometry) Constants() (*LineConstants, error) {
+	if err := g.validate(); err != nil {
+		return nil, fmt.Errorf("Constants: %v", err)
+	}
+
+	// Order conductors with the first conductor of each phase as reference, followed by the remaining
+	// bundled conductors and shield wires.
+	order := make([]int, 0, len(g.Conductors))
+	ref := [3]int{-1, -1, -1}
+	for i, cd := range g.Conductors {
+		if cd.Phase > 0 && ref[cd.Phase-1] < 0 {
+			ref[cd.Phase-1] = i
+		}
+	}
+	order = append(order, ref[:]...)
+	for i := range g.Conductors {
+		if i != ref[0] && i != ref[1] && i != ref[2] {
+			order = append(order, i)
+		}
+	}
+
+	n := len(order)
+	w := 2 * math.Pi * g.Frequency
+	de := 658.5 * math.Sqrt(g.Resistivity/g.Frequency) // Equivalent earth return depth, m.
+	z := newMatrix(n)
+	p := newMatrix(n)
+	for i, ci := range order {
+		a := g.Conductors[ci]
+		for j, cj := range order {
+			b := g.Conductors[cj]
+			if i == j {
+				z[i][j] = complex(a.R/1000+w*mu0/8, w*mu0/(2*math.Pi)*math.Log(de/a.GMR))
+				p[i][j] = complex(math.Log(2*a.Y/a.Radius)/(2*math.Pi*eps0), 0)
+				continue
+			}
+			d := math.Hypot(a.X-b.X, a.Y-b.Y)
+			s := math.Hypot(a.X-b.X, a.Y+b.Y)
+			z[i][j] = complex(w*mu0/8, w*mu0/(2*math.Pi)*math.Log(de/d))
+			p[i][j] = complex(math.Log(s/d)/(2*math.Pi*eps0), 0)
+		}
+	}
+
+	// Bundled conductors share the phase voltage, with the phase current split between them.
+	for k := 3; k < n; k++ {
+		ph := g.Conductors[order[k]].Phase
+		if ph == 0 {
+			continue
+		}
+		bundle(z, ph-1, k)
+		bundle(p, ph-1, k)
+	}
+
+	// Eliminate bundled conductors and grounded shield wires.
+	for k := n - 1; k >= 3; k-- {
+		z = kron(z, k)
+		p = kron(p, k)
+	}
+
+	var lc LineConstants
+	pinv, err := invert(p)
+	if err != nil {
+		return nil, fmt.Errorf("Constants: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			lc.Zabc[i][j] = z[i][j] * 1000
+			lc.Yabc[i][j] = complex(0, w) * pinv[i][j] * 1000
+		}
+	}
+
+	// Transposed line sequence values.
+	zs, zm := average(z)
+	lc.Z1 = (zs - zm) * 1000
+	lc.Z0 = (zs + 2*zm) * 1000
+	ps, pm := average(p)
+	lc.Y1 = complex(0, w) / (ps - pm) * 1000
+	lc.Y0 = complex(0, w) / (ps + 2*pm) * 1000
+	return &lc, nil
+}
+
+// PerUnit returns the sequence impedances and total shunt admittances in per unit on the provided base for a
+// line of the provided length in km.
+func (lc *LineConstants) PerUnit(base Base, lengthKm float64) (z1, z0, y1, y0 complex128) {
+	l := complex(lengthKm, 0)
+	return base.ImpedanceToPU(lc.Z1 * l), base.ImpedanceToPU(lc.Z0 * l),
+		base.AdmittanceToPU(lc.Y1 * l), base.AdmittanceToPU(lc.Y0 * l)
+}
+
+// lengthUnits maps Oneliner line length units to km. The OlxAPI Reference Manual lists ft, kt (thousand feet), mi,
+// m and km for LNsLengthUnit, kft is accepted as an alias of kt.
+var lengthUnits = map[string]float64{
+	"km":  1,
+	"m":   0.001,
+	"mi":  1.609344,
+	"kt":  0.3048,
+	"kft": 0.3048,
+	"ft":  0.0003048,
+}
+
+// LengthToKm converts the line length in the provided Oneliner length unit, e.g. LNsLengthUnit, to km.
+func LengthToKm(length float64, unit string) (float64, error) {
+	f, ok := lengthUnits[strings.ToLower(strings.TrimSpace(unit))]
+	if !ok {
+		return 0, fmt.Errorf("LengthToKm: unknown length unit %q", unit)
+	}
+	return length * f, nil
+}
+
+// SetLineConstants writes the line constants to the line at the provided handle, scaled by the line length
+// LNdLength in LNsLengthUnit and converted to per unit on the system MVA base and bus1 nominal kV. The total
+// shunt admittance is split equally between the bus1 and bus2 ends. The line is left unchanged if any value cannot
+// be written, see Tx.
+func (c *Client) SetLineConstants(hnd int, lc *LineConstants) error {
+	if eqType, _ := c.EquipmentType(hnd); eqType != TCLine {
+		return fmt.Errorf("SetLineConstants: equipment type must be TCLine")
+	}
+	var length float64
+	var unit string
+	var bus1Hnd int
+	if err := c.GetData(hnd, LNdLength, LNsLengthUnit, LNnBus1Hnd).Scan(&length, &unit, &bus1Hnd); err != nil {
+		return fmt.Errorf("SetLineConstants: could not scan line data %v", err)
+	}
+	km, err := LengthToKm(length, unit)
+	if err != nil {
+		return fmt.Errorf("SetLineConstants: %v", err)
+	}
+	var base Base
+	if err := c.GetData(HNDSYS, SYdBaseMVA).Scan(&base.MVA); err != nil {
+		return fmt.Errorf("SetLineConstants: could not get system base MVA: %v", err)
+	}
+	if err := c.GetData(bus1Hnd, BUSdKVnominal).Scan(&base.KV); err != nil {
+		return fmt.Errorf("SetLineConstants: could not get bus nominal kV: %v", err)
+	}
+	if base.MVA <= 0 || base.KV <= 0 {
+		return fmt.Errorf("SetLineConstants: invalid base %0.2f MVA, %0.2f kV", base.MVA, base.KV)
+	}
+
+	z1, z0, y1, y0 := lc.PerUnit(base, km)
+	values := []struct {
+		token int
+		value float64
+	}{
+		{LNdR, real(z1)}, {LNdX, imag(z1)},
+		{LNdR0, real(z0)}, {LNdX0, imag(z0)},
+		{LNdG1, real(y1) / 2}, {LNdB1, imag(y1) / 2},
+		{LNdG2, real(y1) / 2}, {LNdB2, imag(y1) / 2},
+		{LNdG10, real(y0) / 2}, {LNdB10, imag(y0) / 2},
+		{LNdG20, real(y0) / 2}, {LNdB20, imag(y0) / 2},
+	}
+	// Values are written through a transaction so a failure leaves no partial changes staged on the line.
+	tx := c.Begin()
+	for _, v := range values {
+		if err := tx.SetData(hnd, v.token, v.value); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("SetLineConstants: %v", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("SetLineConstants: %v", err)
+	}
+	return nil
+}
+
+// matrix represents a square complex matrix.
+type matrix [][]complex128
+
+// newMatrix returns a new n by n zero matrix.
+func newMatrix(n int) matrix {
+	m := make(matrix, n)
+	for i := range m {
+		m[i] = make([]complex128, n)
+	}
+	return m
+}
+
+// bundle transforms the matrix for conductor k bundled with reference conductor r, replacing the conductor k
+// current with the phase current and the conductor k voltage with its difference to the reference voltage.
+func bundle(m matrix, r, k int) {
+	for i := range m {
+		m[i][k] -= m[i][r]
+	}
+	for j := range m {
+		m[k][j] -= m[r][j]
+	}
+}
+
+// kron eliminates row and column k from the matrix, for a node with zero voltage.
+func kron(m matrix, k int) matrix {
+	n := len(m)
+	out := newMatrix(n - 1)
+	for i, oi := 0, 0; i < n; i++ {
+		if i == k {
+			continue
+		}
+		for j, oj := 0, 0; j < n; j++ {
+			if j == k {
+				continue
+			}
+			out[oi][oj] = m[i][j] - m[i][k]*m[k][j]/m[k][k]
+			oj++
+		}
+		oi++
+	}
+	return out
+}
+
+// invert returns the inverse of the matrix using Gauss-Jordan elimination with partial pivoting.
+func invert(m matrix) (matrix, error) {
+	n := len(m)
+	a := newMatrix(n)
+	inv := newMatrix(n)
+	for i := range m {
+		copy(a[i], m[i])
+		inv[i][i] = 1
+	}
+	for col := 0; col < n; col++ {
+		piv := col
+		for i := col + 1; i < n; i++ {
+			if abs(a[i][col]) > abs(a[piv][col]) {
+				piv = i
+			}
+		}
+		if abs(a[piv][col]) == 0 {
+			return nil, fmt.Errorf("singular matrix")
+		}
+		a[col], a[piv] = a[piv], a[col]
+		inv[col], inv[piv] = inv[piv], inv[col]
+		d := a[col][col]
+		for j := 0; j < n; j++ {
+			a[col][j] /= d
+			inv[col][j] /= d
+		}
+		for i := 0; i < n; i++ {
+			if i == col {
+				continue
+			}
+			f := a[i][col]
+			for j := 0; j < n; j++ {
+				a[i][j] -= f * a[col][j]
+				inv[i][j] -= f * inv[col][j]
+			}
+		}
+	}
+	return inv, nil
+}
+
+// average returns the average self and mutual terms of a 3x3 matrix.
+func average(m matrix) (self, mutual complex128) {
+	self = (m[0][0] + m[1][1] + m[2][2]) / 3
+	mutual = (m[0][1] + m[1][2] + m[2][0] + m[1][0] + m[2][1] + m[0][2]) / 6
+	return self, mutual
+}
+
+// abs returns the absolute value of a complex number.
+func abs(x complex128) float64 {
+	return math.Hypot(real(x), imag(x))
+}
@@ -0,0 +1,154 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"math"
+	"testing"
+)
+
+// testGeometry returns a flat 138 kV single circuit geometry, optionally with a shield wire.
+func testGeometry(shield bool) *LineGeometry {
+	g := &LineGeometry{
+		Resistivity: 100,
+		Frequency:   60,
+		Conductors: []Conductor{
+			{Phase: 1, X: -4, Y: 15, R: 0.0869, GMR: 0.01137, Radius: 0.01407},
+			{Phase: 2, X: 0, Y: 15, R: 0.0869, GMR: 0.01137, Radius: 0.01407},
+			{Phase: 3, X: 4, Y: 15, R: 0.0869, GMR: 0.01137, Radius: 0.01407},
+		},
+	}
+	if shield {
+		g.Conductors = append(g.Conductors, Conductor{X: 0, Y: 22, R: 1.5, GMR: 0.002, Radius: 0.005})
+	}
+	return g
+}
+
+func TestLineGeometry_Constants(t *testing.T) {
+	g := testGeometry(false)
+	lc, err := g.Constants()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := 2 * math.Pi * 60
+	k := w * mu0 / (2 * math.Pi) * 1000
+	gmd := math.Cbrt(4 * 4 * 8)
+	de := 658.5 * math.Sqrt(100.0/60)
+
+	// Positive sequence, GMD/GMR closed form.
+	if r, x := real(lc.Z1), imag(lc.Z1); !almostEqual(r, 0.0869) || !almostEqual(x, k*math.Log(gmd/0.01137)) {
+		t.Errorf("unexpected Z1 %v", lc.Z1)
+	}
+
+	// Zero sequence, earth return closed form.
+	r0 := 0.0869 + 3*w*mu0/8*1000
+	x0 := 3 * k * math.Log(de/math.Cbrt(0.01137*gmd*gmd))
+	if !almostEqual(real(lc.Z0), r0) || !almostEqual(imag(lc.Z0), x0) {
+		t.Errorf("expected Z0 %v, got %v", complex(r0, x0), lc.Z0)
+	}
+
+	// Shunt admittance is capacitive, with the zero sequence less than the positive sequence.
+	if real(lc.Y1) != 0 || imag(lc.Y1) <= imag(lc.Y0) || imag(lc.Y0) <= 0 {
+		t.Errorf("unexpected Y1 %v, Y0 %v", lc.Y1, lc.Y0)
+	}
+	// Positive sequence capacitance approximately 2πε0/ln(GMD/r) at this height.
+	c1 := 2 * math.Pi * eps0 / math.Log(gmd/0.01407) * 1000
+	if b := imag(lc.Y1); math.Abs(b-w*c1)/(w*c1) > 0.02 {
+		t.Errorf("expected B1 near %v, got %v", w*c1, b)
+	}
+
+	// Phase matrix is symmetric.
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			if abs(lc.Zabc[i][j]-lc.Zabc[j][i]) > 1e-12 || abs(lc.Yabc[i][j]-lc.Yabc[j][i]) > 1e-12 {
+				t.Errorf("phase matrices not symmetric at %d,%d", i, j)
+			}
+		}
+	}
+}
+
+func TestLineGeometry_ShieldWire(t *testing.T) {
+	bare, err := testGeometry(false).Constants()
+	if err != nil {
+		t.Fatal(err)
+	}
+	shielded, err := testGeometry(true).Constants()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imag(shielded.Z0) >= imag(bare.Z0) {
+		t.Errorf("expected shield wire to reduce X0, got %v and %v", shielded.Z0, bare.Z0)
+	}
+	if math.Abs(imag(shielded.Z1)-imag(bare.Z1))/imag(bare.Z1) > 0.01 {
+		t.Errorf("expected shield wire to have little effect on X1, got %v and %v", shielded.Z1, bare.Z1)
+	}
+}
+
+func TestLineGeometry_Bundle(t *testing.T) {
+	g := &LineGeometry{Resistivity: 100, Frequency: 60}
+	for i, x := range []float64{-8, 0, 8} {
+		g.Conductors = append(g.Conductors,
+			Conductor{Phase: i + 1, X: x - 0.225, Y: 20, R: 0.06, GMR: 0.0124, Radius: 0.0157},
+			Conductor{Phase: i + 1, X: x + 0.225, Y: 20, R: 0.06, GMR: 0.0124, Radius: 0.0157},
+		)
+	}
+	lc, err := g.Constants()
+	if err != nil {
+		t.Fatal(err)
+	}
+	k := 2 * math.Pi * 60 * mu0 / (2 * math.Pi) * 1000
+	gmd := math.Cbrt(8 * 8 * 16)
+	x1 := k * math.Log(gmd/math.Sqrt(0.0124*0.45))
+	if math.Abs(real(lc.Z1)-0.03)/0.03 > 0.01 {
+		t.Errorf("expected bundle R1 near 0.03, got %v", real(lc.Z1))
+	}
+	if math.Abs(imag(lc.Z1)-x1)/x1 > 0.01 {
+		t.Errorf("expected bundle X1 near %v, got %v", x1, imag(lc.Z1))
+	}
+}
+
+func TestLineGeometry_Validate(t *testing.T) {
+	tests := map[string]*LineGeometry{
+		"frequency":   {Resistivity: 100, Conductors: testGeometry(false).Conductors},
+		"resistivity": {Frequency: 60, Conductors: testGeometry(false).Conductors},
+		"phase":       {Resistivity: 100, Frequency: 60, Conductors: testGeometry(false).Conductors[:2]},
+		"coincident": {Resistivity: 100, Frequency: 60, Conductors: append(testGeometry(false).Conductors,
+			Conductor{X: 0, Y: 15, GMR: 0.01, Radius: 0.01})},
+	}
+	for name, g := range tests {
+		if _, err := g.Constants(); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestLengthToKm(t *testing.T) {
+	tests := map[string]float64{"km": 10, "MI": 16.09344, "kt": 3.048, "kft": 3.048, "ft": 0.003048, "m": 0.01, " mi ": 16.09344}
+	for unit, expected := range tests {
+		got, err := LengthToKm(10, unit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !almostEqual(got, expected) {
+			t.Errorf("%s: expected %v, got %v", unit, expected, got)
+		}
+	}
+	if _, err := LengthToKm(10, "furlong"); err == nil {
+		t.Error("expected error for unknown unit")
+	}
+}
+
+func TestLineConstants_PerUnit(t *testing.T) {
+	lc := LineConstants{Z1: complex(0.1, 0.5), Z0: complex(0.3, 1.5), Y1: complex(0, 3e-6), Y0: complex(0, 2e-6)}
+	base := Base{MVA: 100, KV: 138}
+	z1, z0, y1, y0 := lc.PerUnit(base, 10)
+	if !almostEqual(real(z1), 1/190.44) || !almostEqual(imag(z0), 15/190.44) {
+		t.Errorf("unexpected impedances %v, %v", z1, z0)
+	}
+	if !almostEqual(imag(y1), 3e-5*190.44) || !almostEqual(imag(y0), 2e-5*190.44) {
+		t.Errorf("unexpected admittances %v, %v", y1, y0)
+	}
+}
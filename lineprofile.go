@@ -0,0 +1,183 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ProfilePoint represents the fault results at a single position along a line.
+type ProfilePoint struct {
+	Percent     float64 // Fault location in percent of line length from the from end.
+	Index       int     // Fault index.
+	Description string
+	Total       SeqPhase
+	From, To    SeqPhase // Line currents at the from and to ends.
+	Relays      []RelayResponse
+}
+
+// Relay returns the response of the relay with the provided handle.
+func (p ProfilePoint) Relay(rlyHnd int) (RelayResponse, bool) {
+	for _, r := range p.Relays {
+		if r.Hnd == rlyHnd {
+			return r, true
+		}
+	}
+	return RelayResponse{}, false
+}
+
+// LineProfile represents fault results along a line, ordered by increasing fault location.
+type LineProfile struct {
+	LineHnd int
+	FromHnd int // From end bus handle.
+	ToHnd   int // To end bus handle.
+	Points  []ProfilePoint
+}
+
+// Percents returns the fault locations of the profile.
+func (p *LineProfile) Percents() []float64 {
+	out := make([]float64, len(p.Points))
+	for i, pt := range p.Points {
+		out[i] = pt.Percent
+	}
+	return out
+}
+
+// TotalCurrent returns the total fault current magnitude of the provided phase, 0 for phase A, at each location.
+func (p *LineProfile) TotalCurrent(phase int) []float64 {
+	out := make([]float64, len(p.Points))
+	for i, pt := range p.Points {
+		out[i] = pt.Total.Phase[phase].Mag()
+	}
+	return out
+}
+
+// RelayTimes returns the operating time of the relay at each location, RelayNoOpTime where the relay does not
+// operate.
+func (p *LineProfile) RelayTimes(rlyHnd int) []float64 {
+	out := make([]float64, len(p.Points))
+	for i, pt := range p.Points {
+		out[i] = RelayNoOpTime
+		if r, ok := pt.Relay(rlyHnd); ok {
+			out[i] = r.Time
+		}
+	}
+	return out
+}
+
+// Crossover represents a location along a line where the relative operating speed of two relays changes.
+type Crossover struct {
+	Percent float64 // Interpolated crossover location.
+	Faster  RelayResponse
+	Slower  RelayResponse // Relay slower beyond the crossover.
+}
+
+func (c Crossover) String() string {
+	return fmt.Sprintf("%s slower than %s beyond %0.1f%%", c.Slower.ID, c.Faster.ID, c.Percent)
+}
+
+// Crossovers returns the locations where relay a and relay b swap operating order, e.g. a primary relay becoming
+// slower than its backup. The location is linearly interpolated between adjacent points.
+func (p *LineProfile) Crossovers(aHnd, bHnd int) []Crossover {
+	var out []Crossover
+	for i := 1; i < len(p.Points); i++ {
+		p0, p1 := p.Points[i-1], p.Points[i]
+		a0, okA0 := p0.Relay(aHnd)
+		b0, okB0 := p0.Relay(bHnd)
+		a1, okA1 := p1.Relay(aHnd)
+		b1, okB1 := p1.Relay(bHnd)
+		if !okA0 || !okB0 || !okA1 || !okB1 {
+			continue
+		}
+		d0, d1 := a0.Time-b0.Time, a1.Time-b1.Time
+		if d0 == 0 || d1 == 0 || (d0 < 0) == (d1 < 0) {
+			continue
+		}
+		x := Crossover{Percent: p0.Percent + (p1.Percent-p0.Percent)*d0/(d0-d1)}
+		if d1 > 0 {
+			x.Slower, x.Faster = a1, b1
+		} else {
+			x.Slower, x.Faster = b1, a1
+		}
+		out = append(out, x)
+	}
+	return out
+}
+
+// scCurrentTerminals returns the short circuit current at each terminal in sequence and phase form.
+func (c *Client) scCurrentTerminals(hnd int) ([]SeqPhase, error) {
+	seq, err := c.GetSCCurrentTerminals(hnd, SCSeqRect)
+	if err != nil {
+		return nil, err
+	}
+	phase, err := c.GetSCCurrentTerminals(hnd, SCPhaseRect)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]SeqPhase, len(seq.Terminals))
+	for i := range out {
+		out[i] = SeqPhase{Seq: seq.Terminals[i].Values, Phase: phase.Terminals[i].Values}
+	}
+	return out, nil
+}
+
+// LineProfile runs auto sequenced intermediate faults along the line with the provided connection, from and to
+// percent of the line length at the provided step, see FaultIntermediateAuto. The total fault current, the line
+// currents at each end and the response of every relay within the provided relay groups are recorded at each
+// location. Each fault location is taken from its auto sequence index, from + step×(index-1). Previous fault results
+// are cleared.
+func (c *Client) LineProfile(lineHnd int, conn FltConn, step, from, to float64, rlyGroupHnds ...int) (*LineProfile, error) {
+	if step <= 0 || from < 0 || to > 100 || from > to {
+		return nil, fmt.Errorf("LineProfile: invalid step %g from %g to %g", step, from, to)
+	}
+	line, err := c.GetLine(lineHnd)
+	if err != nil {
+		return nil, fmt.Errorf("LineProfile: %v", err)
+	}
+	if line.RelayGrp1Hnd == 0 {
+		return nil, fmt.Errorf("LineProfile: line %s has no relay group at the from end", line)
+	}
+	relays, err := c.sweepRelays(rlyGroupHnds)
+	if err != nil {
+		return nil, fmt.Errorf("LineProfile: %v", err)
+	}
+
+	// Intermediate faults are applied relative to the relay group end of the line.
+	cfg := NewFaultConfig(FaultConn(conn), FaultIntermediateAuto(step, from, to), FaultClearPrev(true))
+	if err := c.DoFault(line.RelayGrp1Hnd, cfg); err != nil {
+		return nil, fmt.Errorf("LineProfile: %v", err)
+	}
+
+	p := &LineProfile{LineHnd: lineHnd, FromHnd: line.Bus1.Hnd, ToHnd: line.Bus2.Hnd}
+	for fi := c.NextFault(9); fi.Next(); {
+		// Auto sequenced faults are indexed in order of location from the relay group end.
+		pt := ProfilePoint{Index: fi.Index(), Description: c.FaultDescription(fi.Index())}
+		pt.Percent = from + step*float64(pt.Index-1)
+		if pt.Total, err = c.scCurrent(HNDSC); err != nil {
+			return nil, fmt.Errorf("LineProfile: %0.1f%%: %v", pt.Percent, err)
+		}
+		terminals, err := c.scCurrentTerminals(lineHnd)
+		if err != nil {
+			return nil, fmt.Errorf("LineProfile: %0.1f%%: %v", pt.Percent, err)
+		}
+		if len(terminals) == 2 {
+			pt.From, pt.To = terminals[0], terminals[1]
+		}
+		for _, rly := range relays {
+			resp := RelayResponse{Hnd: rly.Hnd, EqType: rly.EqType, ID: rly.ID}
+			if resp.Time, resp.Op, err = c.GetRelayTime(rly.Hnd, 1, false); err != nil {
+				return nil, fmt.Errorf("LineProfile: %0.1f%%: relay %s: %v", pt.Percent, rly.ID, err)
+			}
+			pt.Relays = append(pt.Relays, resp)
+		}
+		p.Points = append(p.Points, pt)
+	}
+	if len(p.Points) == 0 {
+		return nil, fmt.Errorf("LineProfile: no faults simulated on line %s", line)
+	}
+	sort.SliceStable(p.Points, func(i, j int) bool { return p.Points[i].Percent < p.Points[j].Percent })
+	return p, nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"testing"
+)
+
+func TestLineProfile_Crossovers(t *testing.T) {
+	point := func(pct, primary, backup float64) ProfilePoint {
+		return ProfilePoint{Percent: pct, Relays: []RelayResponse{
+			{Hnd: 1, ID: "PRIMARY", Time: primary},
+			{Hnd: 2, ID: "BACKUP", Time: backup},
+		}}
+	}
+	p := &LineProfile{Points: []ProfilePoint{
+		point(70, 0.2, 0.6),
+		point(80, 0.4, 0.5),
+		point(90, 0.8, 0.4),
+		point(100, RelayNoOpTime, 0.3),
+	}}
+	x := p.Crossovers(1, 2)
+	if len(x) != 1 {
+		t.Fatalf("expected 1 crossover, got %v", x)
+	}
+	if !almostEqual(x[0].Percent, 82) || x[0].Slower.Hnd != 1 || x[0].Faster.Hnd != 2 {
+		t.Errorf("unexpected crossover %v", x[0])
+	}
+	if got, expected := x[0].String(), "PRIMARY slower than BACKUP beyond 82.0%"; got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+	if times := p.RelayTimes(1); len(times) != 4 || times[3] != RelayNoOpTime {
+		t.Errorf("unexpected relay times %v", times)
+	}
+	if times := p.RelayTimes(3); times[0] != RelayNoOpTime {
+		t.Errorf("expected no operation for unknown relay, got %v", times)
+	}
+}
+
+func TestClient_LineProfile(t *testing.T) {
+	c := NewClient()
+	defer c.Release()
+	if err := c.LoadDataFile(testCase); err != nil {
+		t.Fatal(err)
+	}
+	line, err := c.FindLine("FIELDALE", 132, "OHIO", 132, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := c.LineProfile(line.Hnd, AG, 10, 10, 90, line.RelayGrp1Hnd, line.RelayGrp2Hnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Points) != 9 {
+		t.Fatalf("expected 9 points, got %d", len(p.Points))
+	}
+	for i, pct := range p.Percents() {
+		if !almostEqual(pct, float64(10*(i+1))) {
+			t.Errorf("expected %v%%, got %v%%", 10*(i+1), pct)
+		}
+	}
+	// The from end contribution decreases as the fault moves away.
+	if first, last := p.Points[0].From.Phase[0].Mag(), p.Points[8].From.Phase[0].Mag(); last >= first {
+		t.Errorf("expected decreasing from end current, got %v to %v", first, last)
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+// Package lint provides a case data quality linter, checking Oneliner cases for common modeling errors using
+// pluggable rules.
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/readpe/goolx"
+)
+
+// Severity represents the severity of a lint finding.
+type Severity int
+
+// Finding severities.
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+// String implements the stringer interface for the Severity type.
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// ParseSeverity parses a severity name, one of info, warning or error.
+func ParseSeverity(s string) (Severity, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "info":
+		return SeverityInfo, nil
+	case "warning", "warn":
+		return SeverityWarning, nil
+	case "error":
+		return SeverityError, nil
+	}
+	return 0, fmt.Errorf("ParseSeverity: unknown severity %q", s)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (s *Severity) UnmarshalText(b []byte) error {
+	v, err := ParseSeverity(string(b))
+	if err != nil {
+		return err
+	}
+	*s = v
+	return nil
+}
+
+// Finding represents a single rule violation.
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Hnd      int      `json:"hnd"`
+	Object   string   `json:"object"`
+	Message  string   `json:"message"`
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s: %s [%s]", f.Severity, f.Object, f.Message, f.Rule)
+}
+
+// Rule represents a lint rule. Check is called once per run, reporting findings through the provided Context.
+// Findings are reported with the rule Severity unless reported using Context.ReportSeverity.
+type Rule struct {
+	Name        string
+	Description string
+	Severity    Severity
+	Check       func(ctx *Context) error
+}
+
+// Context is passed to rule checks, providing the client and collecting findings.
+type Context struct {
+	Client   *goolx.Client
+	rule     Rule
+	findings []Finding
+}
+
+// Report records a finding for the equipment with the provided handle at the rule severity.
+func (ctx *Context) Report(hnd int, format string, args ...interface{}) {
+	ctx.ReportSeverity(hnd, ctx.rule.Severity, format, args...)
+}
+
+// ReportSeverity records a finding for the equipment with the provided handle at the provided severity.
+func (ctx *Context) ReportSeverity(hnd int, sev Severity, format string, args ...interface{}) {
+	ctx.findings = append(ctx.findings, Finding{
+		Rule:     ctx.rule.Name,
+		Severity: sev,
+		Hnd:      hnd,
+		Object:   ctx.object(hnd),
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// object returns the 1LPF id string for the provided handle, or the handle number if unavailable.
+func (ctx *Context) object(hnd int) string {
+	if ctx.Client == nil || hnd == 0 {
+		return strconv.Itoa(hnd)
+	}
+	id, err := ctx.Client.Print1LPF(hnd)
+	if err != nil || id == "" {
+		return strconv.Itoa(hnd)
+	}
+	return id
+}
+
+// Linter runs a set of rules against a case.
+type Linter struct {
+	rules []Rule
+}
+
+// New returns a new Linter with the provided rules.
+func New(rules ...Rule) *Linter {
+	return &Linter{rules: rules}
+}
+
+// Default returns a new Linter with the default rules, see DefaultRules.
+func Default() *Linter {
+	return New(DefaultRules()...)
+}
+
+// Rules returns the linter rules.
+func (l *Linter) Rules() []Rule {
+	return append([]Rule(nil), l.rules...)
+}
+
+// Register adds rules to the linter. Returns an error if a rule name is already registered.
+func (l *Linter) Register(rules ...Rule) error {
+	for _, r := range rules {
+		if r.Name == "" || r.Check == nil {
+			return fmt.Errorf("Register: rule must have a name and check function")
+		}
+		if _, ok := l.rule(r.Name); ok {
+			return fmt.Errorf("Register: rule %q already registered", r.Name)
+		}
+		l.rules = append(l.rules, r)
+	}
+	return nil
+}
+
+// rule returns the rule with the provided name.
+func (l *Linter) rule(name string) (Rule, bool) {
+	for _, r := range l.rules {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return Rule{}, false
+}
+
+// Only returns a new Linter with only the named rules. Returns an error if a rule name is unknown.
+func (l *Linter) Only(names ...string) (*Linter, error) {
+	var rules []Rule
+	for _, name := range names {
+		r, ok := l.rule(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("Only: unknown rule %q", name)
+		}
+		rules = append(rules, r)
+	}
+	return New(rules...), nil
+}
+
+// Run runs all rules against the case loaded in the client. A rule which fails to complete is reported as an
+// error finding, the remaining rules are still run.
+func (l *Linter) Run(c *goolx.Client) *Report {
+	report := &Report{Rules: l.Rules()}
+	for _, r := range l.rules {
+		ctx := &Context{Client: c, rule: r}
+		if err := r.Check(ctx); err != nil {
+			ctx.ReportSeverity(0, SeverityError, "rule failed: %v", err)
+		}
+		report.Findings = append(report.Findings, ctx.findings...)
+	}
+	return report
+}
+
+// Report represents the results of a lint run.
+type Report struct {
+	Rules    []Rule
+	Findings []Finding
+}
+
+// Count returns the number of findings at or above the provided severity.
+func (r *Report) Count(min Severity) int {
+	var n int
+	for _, f := range r.Findings {
+		if f.Severity >= min {
+			n++
+		}
+	}
+	return n
+}
+
+// Filter returns a new report with only the findings at or above the provided severity.
+func (r *Report) Filter(min Severity) *Report {
+	out := &Report{Rules: r.Rules}
+	for _, f := range r.Findings {
+		if f.Severity >= min {
+			out.Findings = append(out.Findings, f)
+		}
+	}
+	return out
+}
+
+// Sort sorts the findings by descending severity, then rule name and object.
+func (r *Report) Sort() {
+	sort.SliceStable(r.Findings, func(i, j int) bool {
+		a, b := r.Findings[i], r.Findings[j]
+		if a.Severity != b.Severity {
+			return a.Severity > b.Severity
+		}
+		if a.Rule != b.Rule {
+			return a.Rule < b.Rule
+		}
+		return a.Object < b.Object
+	})
+}
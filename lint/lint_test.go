@@ -0,0 +1,202 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/readpe/goolx"
+)
+
+var testCase = `C:\Program Files (x86)\ASPEN\1LPFv15\SAMPLE09.OLR`
+
+func testReport() *Report {
+	rule := func(name string, sev Severity) Rule {
+		return Rule{Name: name, Description: name + " description", Severity: sev, Check: func(ctx *Context) error { return nil }}
+	}
+	return &Report{
+		Rules: []Rule{rule("a", SeverityError), rule("b", SeverityWarning)},
+		Findings: []Finding{
+			{Rule: "b", Severity: SeverityWarning, Hnd: 2, Object: "[BUS] 'B2' 138 kV", Message: "warn"},
+			{Rule: "a", Severity: SeverityError, Hnd: 1, Object: "[LINE] 1", Message: "err"},
+			{Rule: "a", Severity: SeverityInfo, Hnd: 3, Object: "[LINE] 3", Message: "info"},
+		},
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	for _, s := range []Severity{SeverityInfo, SeverityWarning, SeverityError} {
+		got, err := ParseSeverity(s.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != s {
+			t.Errorf("expected %v, got %v", s, got)
+		}
+	}
+	if _, err := ParseSeverity("fatal"); err == nil {
+		t.Error("expected error for unknown severity")
+	}
+}
+
+func TestRun(t *testing.T) {
+	l := New(
+		Rule{Name: "ok", Check: func(ctx *Context) error {
+			ctx.Report(1, "found %d", 1)
+			ctx.ReportSeverity(2, SeverityInfo, "note")
+			return nil
+		}, Severity: SeverityError},
+		Rule{Name: "fail", Check: func(ctx *Context) error { return errors.New("test error") }},
+	)
+	if err := l.Register(Rule{Name: "ok", Check: func(ctx *Context) error { return nil }}); err == nil {
+		t.Error("expected error registering duplicate rule")
+	}
+	r := l.Run(nil)
+	if len(r.Findings) != 3 {
+		t.Fatalf("expected 3 findings, got %v", r.Findings)
+	}
+	if f := r.Findings[0]; f.Rule != "ok" || f.Severity != SeverityError || f.Message != "found 1" || f.Object != "1" {
+		t.Errorf("unexpected finding %v", f)
+	}
+	if f := r.Findings[2]; f.Rule != "fail" || f.Severity != SeverityError || !strings.Contains(f.Message, "test error") {
+		t.Errorf("unexpected rule failure finding %v", f)
+	}
+	if got := r.Count(SeverityError); got != 2 {
+		t.Errorf("expected 2 errors, got %d", got)
+	}
+	if got := len(r.Filter(SeverityWarning).Findings); got != 2 {
+		t.Errorf("expected 2 filtered findings, got %d", got)
+	}
+
+	only, err := l.Only("fail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rules := only.Rules(); len(rules) != 1 || rules[0].Name != "fail" {
+		t.Errorf("unexpected rules %v", rules)
+	}
+	if _, err := l.Only("missing"); err == nil {
+		t.Error("expected error for unknown rule")
+	}
+}
+
+func TestReport_Sort(t *testing.T) {
+	r := testReport()
+	r.Sort()
+	var got []string
+	for _, f := range r.Findings {
+		got = append(got, f.Message)
+	}
+	if strings.Join(got, ",") != "err,warn,info" {
+		t.Errorf("unexpected order %v", got)
+	}
+}
+
+func TestReport_WriteText(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testReport().Write(&buf, FormatText); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "[LINE] 1") || !strings.HasSuffix(out, "1 errors, 1 warnings, 1 info\n") {
+		t.Errorf("unexpected text output:\n%s", out)
+	}
+}
+
+func TestReport_WriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testReport().Write(&buf, FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	var findings []Finding
+	if err := json.Unmarshal(buf.Bytes(), &findings); err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 3 || findings[1].Severity != SeverityError || findings[0].Object != "[BUS] 'B2' 138 kV" {
+		t.Errorf("unexpected findings %v", findings)
+	}
+
+	buf.Reset()
+	if err := (&Report{}).WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("expected empty array, got %s", buf.String())
+	}
+}
+
+func TestReport_WriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testReport().Write(&buf, FormatSARIF); err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected sarif log %+v", log)
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != 2 || run.Tool.Driver.Rules[0].DefaultConfig.Level != "error" {
+		t.Errorf("unexpected rules %+v", run.Tool.Driver.Rules)
+	}
+	levels := []string{"warning", "error", "note"}
+	for i, res := range run.Results {
+		if res.Level != levels[i] {
+			t.Errorf("result %d: expected level %s, got %s", i, levels[i], res.Level)
+		}
+	}
+	if name := run.Results[1].Locations[0].LogicalLocations[0].Name; name != "[LINE] 1" {
+		t.Errorf("unexpected location %s", name)
+	}
+
+	if err := testReport().Write(&buf, "xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestXROutliers(t *testing.T) {
+	var lines []lineXR
+	for i, xr := range []float64{8, 9, 10, 11, 12, 40} {
+		lines = append(lines, lineXR{hnd: i + 1, kv: 138, xr: xr})
+	}
+	lines = append(lines, lineXR{hnd: 10, kv: 69, xr: 100}, lineXR{hnd: 11, kv: 69, xr: 1})
+	got := xrOutliers(lines, 3, 5)
+	if len(got) != 1 || got[0].hnd != 6 {
+		t.Errorf("expected line 6 outlier, got %v", got)
+	}
+}
+
+func TestTapMismatch(t *testing.T) {
+	if got := tapMismatch(69, 69); got != 0 {
+		t.Errorf("expected 0, got %v", got)
+	}
+	if got := tapMismatch(138, 69); got != 1 {
+		t.Errorf("expected 1, got %v", got)
+	}
+	if got := tapMismatch(138, 0); got != 0 {
+		t.Errorf("expected 0 for missing kV, got %v", got)
+	}
+}
+
+func TestClient_Lint(t *testing.T) {
+	c := goolx.NewClient()
+	defer c.Release()
+	if err := c.LoadDataFile(testCase); err != nil {
+		t.Fatal(err)
+	}
+	r := Default().Run(c)
+	for _, f := range r.Findings {
+		if strings.HasPrefix(f.Message, "rule failed") {
+			t.Errorf("%v", f)
+		}
+	}
+	t.Logf("%d findings", len(r.Findings))
+}
@@ -0,0 +1,371 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package lint
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/readpe/goolx"
+)
+
+// Default rule thresholds.
+var (
+	// XROutlierFactor is the factor from the voltage class median X/R beyond which a line is an outlier.
+	XROutlierFactor = 3.0
+
+	// XRMinClassSize is the minimum number of lines within a voltage class to check for X/R outliers.
+	XRMinClassSize = 5
+
+	// TapTolerance is the allowed per unit deviation of transformer taps from the bus nominal kV.
+	TapTolerance = 0.1
+
+	// ActiveTag is the tag checked on out of service equipment by the default rules.
+	ActiveTag = "active"
+)
+
+// DefaultRules returns the default lint rules.
+func DefaultRules() []Rule {
+	return []Rule{
+		LineImpedance(),
+		LineXROutlier(),
+		LineZeroSequence(),
+		IsolatedBus(),
+		DuplicateCircuitID(),
+		RelayCTRatio(),
+		EmptyRelayGroup(),
+		TransformerTapKV(),
+		OutOfServiceTagged(ActiveTag),
+	}
+}
+
+// LineImpedance reports lines with zero or negative impedance.
+func LineImpedance() Rule {
+	return Rule{
+		Name:        "line-impedance",
+		Description: "Line with zero or negative impedance",
+		Severity:    SeverityError,
+		Check: func(ctx *Context) error {
+			c := ctx.Client
+			for li := c.NextEquipment(goolx.TCLine); li.Next(); {
+				var r, x float64
+				if err := c.GetData(li.Hnd(), goolx.LNdR, goolx.LNdX).Scan(&r, &x); err != nil {
+					return err
+				}
+				switch {
+				case r == 0 && x == 0:
+					ctx.Report(li.Hnd(), "zero impedance")
+				case r < 0 || x < 0:
+					ctx.Report(li.Hnd(), "negative impedance R=%g X=%g", r, x)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// lineXR represents a line X/R ratio within a voltage class.
+type lineXR struct {
+	hnd int
+	kv  float64
+	xr  float64
+}
+
+// xrOutliers returns the lines whose X/R ratio is beyond factor times, or below 1/factor times, the median of
+// their voltage class. Voltage classes with fewer than minSize lines are not checked.
+func xrOutliers(lines []lineXR, factor float64, minSize int) []lineXR {
+	classes := make(map[float64][]lineXR)
+	for _, l := range lines {
+		kv := math.Round(l.kv*10) / 10
+		classes[kv] = append(classes[kv], l)
+	}
+	var out []lineXR
+	for _, class := range classes {
+		if len(class) < minSize {
+			continue
+		}
+		ratios := make([]float64, len(class))
+		for i, l := range class {
+			ratios[i] = l.xr
+		}
+		med := median(ratios)
+		for _, l := range class {
+			if l.xr > med*factor || l.xr < med/factor {
+				out = append(out, l)
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].hnd < out[j].hnd })
+	return out
+}
+
+// median returns the median of the values, the values are sorted in place.
+func median(v []float64) float64 {
+	sort.Float64s(v)
+	n := len(v)
+	if n%2 == 1 {
+		return v[n/2]
+	}
+	return (v[n/2-1] + v[n/2]) / 2
+}
+
+// LineXROutlier reports lines with an X/R ratio far from the median of lines in the same voltage class, see
+// XROutlierFactor and XRMinClassSize.
+func LineXROutlier() Rule {
+	return Rule{
+		Name:        "line-xr-outlier",
+		Description: "Line X/R ratio outlier within voltage class",
+		Severity:    SeverityWarning,
+		Check: func(ctx *Context) error {
+			c := ctx.Client
+			var lines []lineXR
+			for li := c.NextEquipment(goolx.TCLine); li.Next(); {
+				var r, x float64
+				var bus1Hnd int
+				if err := c.GetData(li.Hnd(), goolx.LNdR, goolx.LNdX, goolx.LNnBus1Hnd).Scan(&r, &x, &bus1Hnd); err != nil {
+					return err
+				}
+				if r <= 0 || x <= 0 {
+					continue
+				}
+				var kv float64
+				if err := c.GetData(bus1Hnd, goolx.BUSdKVnominal).Scan(&kv); err != nil {
+					return err
+				}
+				lines = append(lines, lineXR{hnd: li.Hnd(), kv: kv, xr: x / r})
+			}
+			for _, l := range xrOutliers(lines, XROutlierFactor, XRMinClassSize) {
+				ctx.Report(l.hnd, "X/R %0.1f outlier for %g kV class", l.xr, l.kv)
+			}
+			return nil
+		},
+	}
+}
+
+// LineZeroSequence reports lines with missing zero sequence impedance, or zero sequence impedance equal to the
+// positive sequence impedance.
+func LineZeroSequence() Rule {
+	return Rule{
+		Name:        "line-zero-sequence",
+		Description: "Line zero sequence impedance missing or equal to positive sequence",
+		Severity:    SeverityWarning,
+		Check: func(ctx *Context) error {
+			c := ctx.Client
+			for li := c.NextEquipment(goolx.TCLine); li.Next(); {
+				var r, x, r0, x0 float64
+				if err := c.GetData(li.Hnd(), goolx.LNdR, goolx.LNdX, goolx.LNdR0, goolx.LNdX0).Scan(&r, &x, &r0, &x0); err != nil {
+					return err
+				}
+				switch {
+				case r0 == 0 && x0 == 0:
+					ctx.Report(li.Hnd(), "zero sequence impedance missing")
+				case r0 == r && x0 == x:
+					ctx.Report(li.Hnd(), "zero sequence impedance equal to positive sequence")
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// IsolatedBus reports buses without any connected branches.
+func IsolatedBus() Rule {
+	return Rule{
+		Name:        "isolated-bus",
+		Description: "Bus without connected branches",
+		Severity:    SeverityWarning,
+		Check: func(ctx *Context) error {
+			c := ctx.Client
+			for bi := c.NextEquipment(goolx.TCBus); bi.Next(); {
+				if !c.NextBusEquipment(bi.Hnd(), goolx.TCBranch).Next() {
+					ctx.Report(bi.Hnd(), "no connected branches")
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// branchTokens represents the bus, circuit id and in-service flag tokens for a branch equipment type.
+type branchTokens struct {
+	eqType         int
+	bus1, bus2, id int
+	inService      int
+}
+
+// branchTypes lists the two terminal branch equipment types checked by the branch rules.
+var branchTypes = []branchTokens{
+	{goolx.TCLine, goolx.LNnBus1Hnd, goolx.LNnBus2Hnd, goolx.LNsID, goolx.LNnInService},
+	{goolx.TCXFMR, goolx.XRnBus1Hnd, goolx.XRnBus2Hnd, goolx.XRsID, goolx.XRnInService},
+	{goolx.TCPS, goolx.PSnBus1Hnd, goolx.PSnBus2Hnd, goolx.PSsID, goolx.PSnInService},
+	{goolx.TCSCAP, goolx.SCnBus1Hnd, goolx.SCnBus2Hnd, goolx.SCsID, goolx.SCnInService},
+	{goolx.TCSwitch, goolx.SWnBus1Hnd, goolx.SWnBus2Hnd, goolx.SWsID, goolx.SWnInService},
+}
+
+// DuplicateCircuitID reports branches sharing a circuit id with another branch between the same pair of buses.
+func DuplicateCircuitID() Rule {
+	return Rule{
+		Name:        "duplicate-circuit-id",
+		Description: "Duplicate circuit id between the same bus pair",
+		Severity:    SeverityError,
+		Check: func(ctx *Context) error {
+			c := ctx.Client
+			type key struct {
+				bus1, bus2 int
+				id         string
+			}
+			seen := make(map[key]int)
+			for _, bt := range branchTypes {
+				for ei := c.NextEquipment(bt.eqType); ei.Next(); {
+					var k key
+					if err := c.GetData(ei.Hnd(), bt.bus1, bt.bus2, bt.id).Scan(&k.bus1, &k.bus2, &k.id); err != nil {
+						return err
+					}
+					if k.bus1 > k.bus2 {
+						k.bus1, k.bus2 = k.bus2, k.bus1
+					}
+					k.id = strings.TrimSpace(k.id)
+					if first, ok := seen[k]; ok {
+						ctx.Report(ei.Hnd(), "circuit id %q duplicates %s", k.id, ctx.object(first))
+						continue
+					}
+					seen[k] = ei.Hnd()
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// RelayCTRatio reports overcurrent and distance relays with a zero or negative CT ratio.
+func RelayCTRatio() Rule {
+	relays := []struct {
+		eqType, ct int
+	}{
+		{goolx.TCRLYOCG, goolx.OGdCT},
+		{goolx.TCRLYOCP, goolx.OPdCT},
+		{goolx.TCRLYDSG, goolx.DGdCT},
+		{goolx.TCRLYDSP, goolx.DPdCT},
+	}
+	return Rule{
+		Name:        "relay-ct-ratio",
+		Description: "Relay with zero CT ratio",
+		Severity:    SeverityError,
+		Check: func(ctx *Context) error {
+			c := ctx.Client
+			for _, r := range relays {
+				for ri := c.NextEquipment(r.eqType); ri.Next(); {
+					var ct float64
+					if err := c.GetData(ri.Hnd(), r.ct).Scan(&ct); err != nil {
+						return err
+					}
+					if ct <= 0 {
+						ctx.Report(ri.Hnd(), "CT ratio %g", ct)
+					}
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// EmptyRelayGroup reports relay groups without any protective devices.
+func EmptyRelayGroup() Rule {
+	return Rule{
+		Name:        "empty-relay-group",
+		Description: "Relay group without devices",
+		Severity:    SeverityWarning,
+		Check: func(ctx *Context) error {
+			c := ctx.Client
+			for gi := c.NextEquipment(goolx.TCRLYGroup); gi.Next(); {
+				if !c.NextRelay(gi.Hnd()).Next() {
+					ctx.Report(gi.Hnd(), "no protective devices")
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// tapMismatch returns the per unit deviation of the tap from the bus nominal kV.
+func tapMismatch(tap, kv float64) float64 {
+	if kv == 0 {
+		return 0
+	}
+	return math.Abs(tap/kv - 1)
+}
+
+// TransformerTapKV reports two winding transformers with taps deviating from the bus nominal kV by more than
+// TapTolerance.
+func TransformerTapKV() Rule {
+	return Rule{
+		Name:        "xfmr-tap-kv",
+		Description: "Transformer tap mismatched with bus nominal kV",
+		Severity:    SeverityWarning,
+		Check: func(ctx *Context) error {
+			c := ctx.Client
+			for xi := c.NextEquipment(goolx.TCXFMR); xi.Next(); {
+				var bus1Hnd, bus2Hnd int
+				var priTap, secTap float64
+				if err := c.GetData(xi.Hnd(), goolx.XRnBus1Hnd, goolx.XRnBus2Hnd, goolx.XRdPriTap, goolx.XRdSecTap).Scan(
+					&bus1Hnd, &bus2Hnd, &priTap, &secTap); err != nil {
+					return err
+				}
+				var kv1, kv2 float64
+				if err := c.GetData(bus1Hnd, goolx.BUSdKVnominal).Scan(&kv1); err != nil {
+					return err
+				}
+				if err := c.GetData(bus2Hnd, goolx.BUSdKVnominal).Scan(&kv2); err != nil {
+					return err
+				}
+				if tapMismatch(priTap, kv1) > TapTolerance {
+					ctx.Report(xi.Hnd(), "primary tap %g kV, bus %g kV", priTap, kv1)
+				}
+				if tapMismatch(secTap, kv2) > TapTolerance {
+					ctx.Report(xi.Hnd(), "secondary tap %g kV, bus %g kV", secTap, kv2)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// OutOfServiceTagged reports out of service branches and relay groups carrying the provided tag.
+func OutOfServiceTagged(tag string) Rule {
+	types := append([]branchTokens(nil), branchTypes...)
+	types = append(types,
+		branchTokens{eqType: goolx.TCXFMR3, inService: goolx.X3nInService},
+		branchTokens{eqType: goolx.TCRLYGroup, inService: goolx.RGnInService},
+	)
+	return Rule{
+		Name:        "out-of-service-tagged",
+		Description: fmt.Sprintf("Out of service equipment tagged %q", tag),
+		Severity:    SeverityWarning,
+		Check: func(ctx *Context) error {
+			c := ctx.Client
+			for _, bt := range types {
+				for ei := c.NextEquipment(bt.eqType); ei.Next(); {
+					var flag int
+					if err := c.GetData(ei.Hnd(), bt.inService).Scan(&flag); err != nil {
+						return err
+					}
+					if flag != goolx.InServiceOff {
+						continue
+					}
+					tags, _ := c.TagsGet(ei.Hnd())
+					for _, t := range tags {
+						if strings.EqualFold(t, tag) {
+							ctx.Report(ei.Hnd(), "out of service, tagged %q", t)
+							break
+						}
+					}
+				}
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,442 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Metadata block delimiters within the object memo field.
+const (
+	MetadataBegin = "[goolx:metadata]"
+	MetadataEnd   = "[/goolx:metadata]"
+)
+
+// MetadataDateLayout is the layout used to store date only values, see SetDate.
+const MetadataDateLayout = "2006-01-02"
+
+// Metadata represents structured key/value data stored in a delimited block within an object memo field.
+// Any human written text outside of the block is preserved when metadata is written back. Key order is preserved.
+type Metadata struct {
+	keys   []string
+	values map[string]string
+}
+
+// ParseMetadata parses the metadata block from the provided memo string. Returns the metadata and the remaining
+// memo text with the block removed. An error is returned if the block is not terminated or contains malformed lines.
+func ParseMetadata(memo string) (*Metadata, string, error) {
+	md := &Metadata{values: make(map[string]string)}
+	lines := strings.Split(memo, "\n")
+	var text []string
+	var inBlock, found bool
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case !inBlock && !found && trimmed == MetadataBegin:
+			inBlock, found = true, true
+		case inBlock && trimmed == MetadataEnd:
+			inBlock = false
+		case inBlock:
+			if trimmed == "" {
+				continue
+			}
+			k, v, ok := strings.Cut(trimmed, "=")
+			if !ok {
+				return nil, memo, fmt.Errorf("ParseMetadata: line %d: missing '=' in %q", i+1, trimmed)
+			}
+			if err := md.Set(strings.TrimSpace(k), strings.TrimSpace(v)); err != nil {
+				return nil, memo, fmt.Errorf("ParseMetadata: line %d: %v", i+1, err)
+			}
+		default:
+			text = append(text, line)
+		}
+	}
+	if inBlock {
+		return nil, memo, fmt.Errorf("ParseMetadata: missing %s", MetadataEnd)
+	}
+	return md, strings.Join(text, "\n"), nil
+}
+
+// FormatMetadata returns the memo string combining the provided text with the metadata block appended.
+// If the metadata is empty, only the text is returned.
+func FormatMetadata(text string, md *Metadata) string {
+	if md == nil || md.Len() == 0 {
+		return text
+	}
+	var sb strings.Builder
+	text = strings.TrimRight(text, "\r\n")
+	if text != "" {
+		sb.WriteString(text)
+		sb.WriteString("\n")
+	}
+	sb.WriteString(MetadataBegin)
+	sb.WriteString("\n")
+	for _, k := range md.keys {
+		fmt.Fprintf(&sb, "%s=%s\n", k, md.values[k])
+	}
+	sb.WriteString(MetadataEnd)
+	return sb.String()
+}
+
+// validMetadataKey reports whether k is a valid metadata key. Keys are made up of letters, digits, '_', '-' and '.'.
+func validMetadataKey(k string) bool {
+	if k == "" {
+		return false
+	}
+	for _, r := range k {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Len returns the number of keys within the metadata.
+func (md *Metadata) Len() int {
+	return len(md.keys)
+}
+
+// Keys returns the metadata keys in insertion order.
+func (md *Metadata) Keys() []string {
+	keys := make([]string, len(md.keys))
+	copy(keys, md.keys)
+	return keys
+}
+
+// Get returns the string value for the provided key, and whether the key exists.
+func (md *Metadata) Get(key string) (string, bool) {
+	v, ok := md.values[key]
+	return v, ok
+}
+
+// Set sets the string value for the provided key. Returns an error if the key is invalid or the value
+// spans multiple lines.
+func (md *Metadata) Set(key, value string) error {
+	if !validMetadataKey(key) {
+		return fmt.Errorf("Set: invalid metadata key %q", key)
+	}
+	if strings.ContainsAny(value, "\r\n") {
+		return fmt.Errorf("Set: metadata value for key %q must be a single line", key)
+	}
+	if md.values == nil {
+		md.values = make(map[string]string)
+	}
+	if _, ok := md.values[key]; !ok {
+		md.keys = append(md.keys, key)
+	}
+	md.values[key] = value
+	return nil
+}
+
+// Delete removes the provided key. Reports whether the key existed.
+func (md *Metadata) Delete(key string) bool {
+	if _, ok := md.values[key]; !ok {
+		return false
+	}
+	delete(md.values, key)
+	for i, k := range md.keys {
+		if k == key {
+			md.keys = append(md.keys[:i], md.keys[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// lookup returns the value for the key, or an error if the key does not exist.
+func (md *Metadata) lookup(key string) (string, error) {
+	v, ok := md.values[key]
+	if !ok {
+		return "", fmt.Errorf("metadata key %q not found", key)
+	}
+	return v, nil
+}
+
+// Int returns the value for the provided key parsed as an int.
+func (md *Metadata) Int(key string) (int, error) {
+	v, err := md.lookup(key)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(v)
+}
+
+// Float returns the value for the provided key parsed as a float64.
+func (md *Metadata) Float(key string) (float64, error) {
+	v, err := md.lookup(key)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(v, 64)
+}
+
+// Bool returns the value for the provided key parsed as a bool.
+func (md *Metadata) Bool(key string) (bool, error) {
+	v, err := md.lookup(key)
+	if err != nil {
+		return false, err
+	}
+	return strconv.ParseBool(v)
+}
+
+// Time returns the value for the provided key parsed as a time. RFC 3339 and date only (2006-01-02)
+// values are supported.
+func (md *Metadata) Time(key string) (time.Time, error) {
+	v, err := md.lookup(key)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return parseMetadataTime(v)
+}
+
+// parseMetadataTime parses RFC 3339 or date only time strings.
+func parseMetadataTime(s string) (time.Time, error) {
+	if t, err := time.Parse(MetadataDateLayout, s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// SetInt sets the value for the provided key to the int value.
+func (md *Metadata) SetInt(key string, v int) error {
+	return md.Set(key, strconv.Itoa(v))
+}
+
+// SetFloat sets the value for the provided key to the float64 value.
+func (md *Metadata) SetFloat(key string, v float64) error {
+	return md.Set(key, strconv.FormatFloat(v, 'g', -1, 64))
+}
+
+// SetBool sets the value for the provided key to the bool value.
+func (md *Metadata) SetBool(key string, v bool) error {
+	return md.Set(key, strconv.FormatBool(v))
+}
+
+// SetTime sets the value for the provided key to the time formatted as RFC 3339.
+func (md *Metadata) SetTime(key string, t time.Time) error {
+	return md.Set(key, t.Format(time.RFC3339))
+}
+
+// SetDate sets the value for the provided key to the date of the provided time, see MetadataDateLayout.
+func (md *Metadata) SetDate(key string, t time.Time) error {
+	return md.Set(key, t.Format(MetadataDateLayout))
+}
+
+// MetadataType represents the value type of a metadata schema field.
+type MetadataType int
+
+// Metadata value types.
+const (
+	MetadataString MetadataType = iota
+	MetadataInt
+	MetadataFloat
+	MetadataBool
+	MetadataTime // RFC 3339 or date only, see Metadata.Time.
+)
+
+// String implements the stringer interface for the MetadataType type.
+func (t MetadataType) String() string {
+	switch t {
+	case MetadataString:
+		return "string"
+	case MetadataInt:
+		return "int"
+	case MetadataFloat:
+		return "float"
+	case MetadataBool:
+		return "bool"
+	case MetadataTime:
+		return "time"
+	}
+	return fmt.Sprintf("MetadataType(%d)", int(t))
+}
+
+// MetadataField represents a single field definition within a MetadataSchema.
+type MetadataField struct {
+	Key      string
+	Type     MetadataType
+	Required bool
+}
+
+// MetadataSchema represents the expected metadata fields. Keys not within the schema are reported as
+// errors when Strict is true.
+type MetadataSchema struct {
+	Fields []MetadataField
+	Strict bool
+}
+
+// MetadataError represents the list of schema violations found by MetadataSchema.Validate.
+type MetadataError []string
+
+// Error implements the error interface for the MetadataError type.
+func (e MetadataError) Error() string {
+	return "metadata validation failed: " + strings.Join(e, "; ")
+}
+
+// Validate checks the provided metadata against the schema. Returns a MetadataError listing every
+// violation, or nil if valid.
+func (s MetadataSchema) Validate(md *Metadata) error {
+	var errs MetadataError
+	known := make(map[string]bool)
+	for _, f := range s.Fields {
+		known[f.Key] = true
+		v, ok := md.Get(f.Key)
+		if !ok {
+			if f.Required {
+				errs = append(errs, fmt.Sprintf("missing required key %q", f.Key))
+			}
+			continue
+		}
+		var err error
+		switch f.Type {
+		case MetadataInt:
+			_, err = strconv.Atoi(v)
+		case MetadataFloat:
+			_, err = strconv.ParseFloat(v, 64)
+		case MetadataBool:
+			_, err = strconv.ParseBool(v)
+		case MetadataTime:
+			_, err = parseMetadataTime(v)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("key %q value %q is not of type %v", f.Key, v, f.Type))
+		}
+	}
+	if s.Strict {
+		for _, k := range md.keys {
+			if !known[k] {
+				errs = append(errs, fmt.Sprintf("unknown key %q", k))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// MetadataGet returns the metadata stored within the object memo field.
+func (c *Client) MetadataGet(hnd int) (*Metadata, error) {
+	memo, err := c.MemoGet(hnd)
+	if err != nil {
+		return nil, err
+	}
+	md, _, err := ParseMetadata(memo)
+	return md, err
+}
+
+// MetadataSet replaces the metadata block within the object memo field, preserving any other memo text.
+// An empty metadata removes the block.
+func (c *Client) MetadataSet(hnd int, md *Metadata) error {
+	memo, err := c.MemoGet(hnd)
+	if err != nil {
+		return err
+	}
+	_, text, err := ParseMetadata(memo)
+	if err != nil {
+		return err
+	}
+	return c.MemoSet(hnd, FormatMetadata(text, md))
+}
+
+// MetadataSetKey sets a single metadata key within the object memo field.
+func (c *Client) MetadataSetKey(hnd int, key, value string) error {
+	md, err := c.MetadataGet(hnd)
+	if err != nil {
+		return err
+	}
+	if err := md.Set(key, value); err != nil {
+		return err
+	}
+	return c.MetadataSet(hnd, md)
+}
+
+// MetadataDeleteKey removes a single metadata key from the object memo field.
+func (c *Client) MetadataDeleteKey(hnd int, key string) error {
+	md, err := c.MetadataGet(hnd)
+	if err != nil {
+		return err
+	}
+	if !md.Delete(key) {
+		return nil
+	}
+	return c.MetadataSet(hnd, md)
+}
+
+// MetadataRecord represents the metadata of a single equipment object, see ExportMetadata.
+type MetadataRecord struct {
+	Hnd      int
+	ID       string // 1LPF id string, see Print1LPF.
+	Metadata *Metadata
+}
+
+// ExportMetadata returns the metadata for all equipment of the provided type which have a metadata block.
+// Equipment with malformed metadata blocks return an error.
+func (c *Client) ExportMetadata(eqType int) ([]MetadataRecord, error) {
+	var records []MetadataRecord
+	for ei := c.NextEquipment(eqType); ei.Next(); {
+		hnd := ei.Hnd()
+		memo, err := c.MemoGet(hnd)
+		if err != nil || !strings.Contains(memo, MetadataBegin) {
+			continue
+		}
+		md, _, err := ParseMetadata(memo)
+		if err != nil {
+			return records, fmt.Errorf("ExportMetadata: %s: %v", c.printID(hnd), err)
+		}
+		records = append(records, MetadataRecord{Hnd: hnd, ID: c.printID(hnd), Metadata: md})
+	}
+	return records, nil
+}
+
+// printID returns the 1LPF id string for the provided handle, or the handle number if unavailable.
+func (c *Client) printID(hnd int) string {
+	id, err := c.Print1LPF(hnd)
+	if err != nil || id == "" {
+		return strconv.Itoa(hnd)
+	}
+	return id
+}
+
+// WriteMetadataCSV writes the metadata records to w in csv format, one row per record with the 1LPF id in
+// the first column. If no keys are provided, the sorted union of all record keys is used.
+func WriteMetadataCSV(w io.Writer, records []MetadataRecord, keys ...string) error {
+	if len(keys) == 0 {
+		seen := make(map[string]bool)
+		for _, r := range records {
+			for _, k := range r.Metadata.keys {
+				if !seen[k] {
+					seen[k] = true
+					keys = append(keys, k)
+				}
+			}
+		}
+		sort.Strings(keys)
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(append([]string{"ID"}, keys...)); err != nil {
+		return err
+	}
+	for _, r := range records {
+		row := []string{r.ID}
+		for _, k := range keys {
+			v, _ := r.Metadata.Get(k)
+			row = append(row, v)
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,159 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestParseMetadata(t *testing.T) {
+	memo := "Relay settings reviewed.\n[goolx:metadata]\nreviewer=jdoe\nreview_date=2021-06-01\nrev=12\n[/goolx:metadata]\nField note"
+	md, text, err := ParseMetadata(memo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "Relay settings reviewed.\nField note"; text != expected {
+		t.Errorf("expected text %q, got %q", expected, text)
+	}
+	if v, _ := md.Get("reviewer"); v != "jdoe" {
+		t.Errorf("expected reviewer jdoe, got %q", v)
+	}
+	if rev, err := md.Int("rev"); err != nil || rev != 12 {
+		t.Errorf("expected rev 12, got %d %v", rev, err)
+	}
+	if d, err := md.Time("review_date"); err != nil || !d.Equal(time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected review_date 2021-06-01, got %v %v", d, err)
+	}
+
+	md.Delete("rev")
+	if err := md.SetFloat("kv", 138); err != nil {
+		t.Fatal(err)
+	}
+	got := FormatMetadata(text, md)
+	expected := "Relay settings reviewed.\nField note\n[goolx:metadata]\nreviewer=jdoe\nreview_date=2021-06-01\nkv=138\n[/goolx:metadata]"
+	if got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+
+	// Round trip.
+	md2, text2, err := ParseMetadata(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text2 != text || FormatMetadata(text2, md2) != got {
+		t.Errorf("round trip mismatch, got %q", FormatMetadata(text2, md2))
+	}
+}
+
+func TestParseMetadata_Errors(t *testing.T) {
+	tests := map[string]string{
+		"unterminated": "[goolx:metadata]\na=1",
+		"missing =":    "[goolx:metadata]\na\n[/goolx:metadata]",
+		"invalid key":  "[goolx:metadata]\na b=1\n[/goolx:metadata]",
+	}
+	for name, memo := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := ParseMetadata(memo); err == nil {
+				t.Errorf("expected error, got nil")
+			}
+		})
+	}
+	md := &Metadata{}
+	if err := md.Set("a", "line1\nline2"); err == nil {
+		t.Errorf("expected multi-line value error, got nil")
+	}
+}
+
+func TestFormatMetadata_Empty(t *testing.T) {
+	if got := FormatMetadata("text", &Metadata{}); got != "text" {
+		t.Errorf("expected %q, got %q", "text", got)
+	}
+}
+
+func TestMetadataSchema_Validate(t *testing.T) {
+	schema := MetadataSchema{
+		Fields: []MetadataField{
+			{Key: "reviewer", Type: MetadataString, Required: true},
+			{Key: "review_date", Type: MetadataTime, Required: true},
+			{Key: "rev", Type: MetadataInt},
+		},
+		Strict: true,
+	}
+	md := &Metadata{}
+	md.Set("reviewer", "jdoe")
+	md.SetDate("review_date", time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC))
+	md.SetInt("rev", 3)
+	if err := schema.Validate(md); err != nil {
+		t.Errorf("expected valid metadata, got %v", err)
+	}
+
+	md.Delete("reviewer")
+	md.Set("rev", "three")
+	md.Set("extra", "x")
+	err := schema.Validate(md)
+	errs, ok := err.(MetadataError)
+	if !ok {
+		t.Fatalf("expected MetadataError, got %v", err)
+	}
+	if len(errs) != 3 {
+		t.Errorf("expected 3 violations, got %d: %v", len(errs), errs)
+	}
+}
+
+func TestWriteMetadataCSV(t *testing.T) {
+	md1 := &Metadata{}
+	md1.Set("reviewer", "jdoe")
+	md2 := &Metadata{}
+	md2.Set("rev", "2")
+	records := []MetadataRecord{
+		{ID: "[BUS] 'A' 132 kV", Metadata: md1},
+		{ID: "[BUS] 'B' 132 kV", Metadata: md2},
+	}
+	var buf bytes.Buffer
+	if err := WriteMetadataCSV(&buf, records); err != nil {
+		t.Fatal(err)
+	}
+	expected := "ID,rev,reviewer\n[BUS] 'A' 132 kV,,jdoe\n[BUS] 'B' 132 kV,2,\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestClient_Metadata(t *testing.T) {
+	c := NewClient()
+	defer c.Release()
+	if err := c.LoadDataFile(testCase); err != nil {
+		t.Fatal(err)
+	}
+	busHnd, err := c.FindBusByName("TENNESSEE", 132)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.MemoSet(busHnd, "Human note"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.MetadataSetKey(busHnd, "reviewer", "jdoe"); err != nil {
+		t.Fatal(err)
+	}
+	md, err := c.MetadataGet(busHnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := md.Get("reviewer"); v != "jdoe" {
+		t.Errorf("expected reviewer jdoe, got %q", v)
+	}
+	if !c.MemoContains(busHnd, "Human note") {
+		t.Errorf("expected memo text to be preserved")
+	}
+	records, err := c.ExportMetadata(TCBus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 {
+		t.Errorf("expected 1 record, got %d", len(records))
+	}
+}
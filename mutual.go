@@ -0,0 +1,191 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"fmt"
+	"sort"
+)
+
+// MutualSection represents a mutually coupled section between two lines. The start and end of the coupled
+// section along each line are given in percent of the line length, measured from the line bus1.
+type MutualSection struct {
+	From1, To1 float64 // Line1 coupled section start and end, percent.
+	From2, To2 float64 // Line2 coupled section start and end, percent.
+	R, X       float64 // Mutual impedance.
+}
+
+// MutualPair represents a mutual coupling pair data object.
+type MutualPair struct {
+	Hnd      int
+	Line1Hnd int
+	Line2Hnd int
+	Orient1  int
+	Orient2  int
+	Sections []MutualSection
+}
+
+func (m *MutualPair) String() string {
+	return fmt.Sprintf("mutual %d-%d sections:%d", m.Line1Hnd, m.Line2Hnd, len(m.Sections))
+}
+
+// Other returns the handle of the line coupled with the provided line, or 0 if the line is not part of the pair.
+func (m *MutualPair) Other(lineHnd int) int {
+	switch lineHnd {
+	case m.Line1Hnd:
+		return m.Line2Hnd
+	case m.Line2Hnd:
+		return m.Line1Hnd
+	}
+	return 0
+}
+
+// GetMutualPair loads the mutual pair data at the provided handle into a new MutualPair object. Returns error
+// if the handle provided does not point to an equipment type TCMU.
+func (c *Client) GetMutualPair(hnd int) (*MutualPair, error) {
+	if eqType, _ := c.EquipmentType(hnd); eqType != TCMU {
+		return nil, fmt.Errorf("GetMutualPair: equipment type must be TCMU")
+	}
+	var mu = MutualPair{Hnd: hnd}
+	data := c.GetData(hnd,
+		MUnHndLine1,
+		MUnHndLine2,
+		MUnOrient1,
+		MUnOrient2,
+	)
+	if err := data.Scan(
+		&mu.Line1Hnd,
+		&mu.Line2Hnd,
+		&mu.Orient1,
+		&mu.Orient2,
+	); err != nil {
+		return nil, fmt.Errorf("GetMutualPair: could not scan mutual data %v", err)
+	}
+
+	// Multiple sections are read from the array tokens, unused array entries are zero.
+	var x, r, from1, to1, from2, to2 []float64
+	err := c.GetData(hnd, MUvdX, MUvdR, MUvdFrom1, MUvdTo1, MUvdFrom2, MUvdTo2).Scan(&x, &r, &from1, &to1, &from2, &to2)
+	if err == nil {
+		for i := range x {
+			if x[i] == 0 && r[i] == 0 {
+				continue
+			}
+			mu.Sections = append(mu.Sections, MutualSection{
+				From1: from1[i], To1: to1[i],
+				From2: from2[i], To2: to2[i],
+				R: r[i], X: x[i],
+			})
+		}
+		return &mu, nil
+	}
+
+	// Fall back to the single section tokens.
+	var sec MutualSection
+	if err := c.GetData(hnd, MUdFrom1, MUdTo1, MUdFrom2, MUdTo2, MUdR, MUdX).Scan(
+		&sec.From1, &sec.To1,
+		&sec.From2, &sec.To2,
+		&sec.R, &sec.X,
+	); err != nil {
+		return nil, fmt.Errorf("GetMutualPair: could not scan mutual section data %v", err)
+	}
+	mu.Sections = append(mu.Sections, sec)
+	return &mu, nil
+}
+
+// MutualPairs returns all mutual pairs in the case.
+func (c *Client) MutualPairs() ([]*MutualPair, error) {
+	var pairs []*MutualPair
+	for mi := c.NextEquipment(TCMU); mi.Next(); {
+		mu, err := c.GetMutualPair(mi.Hnd())
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, mu)
+	}
+	return pairs, nil
+}
+
+// MutualGroup represents a corridor of mutually coupled lines. Lines within a group are coupled directly,
+// or through a chain of mutual pairs.
+type MutualGroup struct {
+	Lines []int // Line handles, sorted.
+	Pairs []*MutualPair
+}
+
+// Contains reports whether the line is within the group.
+func (g MutualGroup) Contains(lineHnd int) bool {
+	i := sort.SearchInts(g.Lines, lineHnd)
+	return i < len(g.Lines) && g.Lines[i] == lineHnd
+}
+
+// GroupMutualPairs groups the provided mutual pairs into corridors of mutually coupled lines, following
+// chains of pairs. Groups are ordered by their lowest line handle.
+func GroupMutualPairs(pairs []*MutualPair) []MutualGroup {
+	// Union-find over line handles.
+	parent := make(map[int]int)
+	var find func(int) int
+	find = func(x int) int {
+		if _, ok := parent[x]; !ok {
+			parent[x] = x
+		}
+		if parent[x] != x {
+			parent[x] = find(parent[x])
+		}
+		return parent[x]
+	}
+	for _, p := range pairs {
+		a, b := find(p.Line1Hnd), find(p.Line2Hnd)
+		if a != b {
+			parent[b] = a
+		}
+	}
+
+	byRoot := make(map[int]*MutualGroup)
+	for line := range parent {
+		root := find(line)
+		g, ok := byRoot[root]
+		if !ok {
+			g = &MutualGroup{}
+			byRoot[root] = g
+		}
+		g.Lines = append(g.Lines, line)
+	}
+	for _, p := range pairs {
+		g := byRoot[find(p.Line1Hnd)]
+		g.Pairs = append(g.Pairs, p)
+	}
+
+	groups := make([]MutualGroup, 0, len(byRoot))
+	for _, g := range byRoot {
+		sort.Ints(g.Lines)
+		groups = append(groups, *g)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Lines[0] < groups[j].Lines[0] })
+	return groups
+}
+
+// MutualGroups returns all corridors of mutually coupled lines in the case, see GroupMutualPairs.
+func (c *Client) MutualGroups() ([]MutualGroup, error) {
+	pairs, err := c.MutualPairs()
+	if err != nil {
+		return nil, err
+	}
+	return GroupMutualPairs(pairs), nil
+}
+
+// MutualGroupOf returns the corridor of mutually coupled lines containing the provided line. Returns a group
+// containing only the line if it is not mutually coupled.
+func (c *Client) MutualGroupOf(lineHnd int) (MutualGroup, error) {
+	groups, err := c.MutualGroups()
+	if err != nil {
+		return MutualGroup{}, err
+	}
+	for _, g := range groups {
+		if g.Contains(lineHnd) {
+			return g, nil
+		}
+	}
+	return MutualGroup{Lines: []int{lineHnd}}, nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestGroupMutualPairs(t *testing.T) {
+	pairs := []*MutualPair{
+		{Hnd: 1, Line1Hnd: 10, Line2Hnd: 11},
+		{Hnd: 2, Line1Hnd: 12, Line2Hnd: 11}, // chained through line 11
+		{Hnd: 3, Line1Hnd: 20, Line2Hnd: 21},
+		{Hnd: 4, Line1Hnd: 10, Line2Hnd: 12}, // closes loop 10-11-12
+	}
+	groups := GroupMutualPairs(pairs)
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(groups))
+	}
+	if got := fmt.Sprint(groups[0].Lines); got != "[10 11 12]" {
+		t.Errorf("expected [10 11 12], got %s", got)
+	}
+	if len(groups[0].Pairs) != 3 {
+		t.Errorf("expected 3 pairs, got %d", len(groups[0].Pairs))
+	}
+	if got := fmt.Sprint(groups[1].Lines); got != "[20 21]" {
+		t.Errorf("expected [20 21], got %s", got)
+	}
+	if !groups[0].Contains(12) || groups[0].Contains(20) {
+		t.Errorf("unexpected Contains results for %v", groups[0].Lines)
+	}
+	if other := pairs[1].Other(11); other != 12 {
+		t.Errorf("expected 12, got %d", other)
+	}
+}
+
+func TestClient_MutualGroups(t *testing.T) {
+	c := NewClient()
+	defer c.Release()
+	if err := c.LoadDataFile(testCase); err != nil {
+		t.Fatal(err)
+	}
+	pairs, err := c.MutualPairs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pairs) == 0 {
+		t.Skip("no mutual pairs in test case")
+	}
+	for _, p := range pairs {
+		if len(p.Sections) == 0 {
+			t.Errorf("expected at least one section for %v", p)
+		}
+	}
+	g, err := c.MutualGroupOf(pairs[0].Line1Hnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.Contains(pairs[0].Line2Hnd) {
+		t.Errorf("expected group %v to contain line %d", g.Lines, pairs[0].Line2Hnd)
+	}
+}
@@ -0,0 +1,470 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// MatchMode represents the name matching mode used by name searches.
+type MatchMode int
+
+// Name matching modes.
+const (
+	MatchExact  MatchMode = iota // Case insensitive exact match.
+	MatchPrefix                  // Case insensitive prefix match.
+	MatchRegex                   // Case insensitive regular expression match.
+	MatchFuzzy                   // Edit distance match, including partial names.
+)
+
+// String implements the stringer interface for the MatchMode type.
+func (m MatchMode) String() string {
+	switch m {
+	case MatchExact:
+		return "exact"
+	case MatchPrefix:
+		return "prefix"
+	case MatchRegex:
+		return "regex"
+	case MatchFuzzy:
+		return "fuzzy"
+	}
+	return fmt.Sprintf("MatchMode(%d)", int(m))
+}
+
+// searchConfig represents the name search configuration.
+type searchConfig struct {
+	mode        MatchMode
+	kv, kvTol   float64
+	toKV, toTol float64
+	ckt         string
+	limit       int
+	maxDist     int
+}
+
+// SearchOption represents a name search option.
+type SearchOption func(*searchConfig)
+
+// SearchMatch sets the name matching mode, default MatchFuzzy.
+func SearchMatch(mode MatchMode) SearchOption {
+	return func(cfg *searchConfig) {
+		cfg.mode = mode
+	}
+}
+
+// SearchKV limits bus matches to buses with nominal kV within tol per unit of kv. For branch searches this
+// applies to the from bus, see SearchToKV.
+func SearchKV(kv, tol float64) SearchOption {
+	return func(cfg *searchConfig) {
+		cfg.kv, cfg.kvTol = kv, tol
+	}
+}
+
+// SearchToKV limits the branch search to bus matches to buses with nominal kV within tol per unit of kv.
+func SearchToKV(kv, tol float64) SearchOption {
+	return func(cfg *searchConfig) {
+		cfg.toKV, cfg.toTol = kv, tol
+	}
+}
+
+// SearchCktID limits branch searches to branches with the circuit id.
+func SearchCktID(ckt string) SearchOption {
+	return func(cfg *searchConfig) {
+		cfg.ckt = ckt
+	}
+}
+
+// SearchLimit limits the number of returned candidates, zero for no limit. Default 10.
+func SearchLimit(n int) SearchOption {
+	return func(cfg *searchConfig) {
+		cfg.limit = n
+	}
+}
+
+// SearchMaxDistance sets the maximum edit distance for fuzzy matching. Defaults to one third of the query
+// length, at least one.
+func SearchMaxDistance(d int) SearchOption {
+	return func(cfg *searchConfig) {
+		cfg.maxDist = d
+	}
+}
+
+// newSearchConfig returns the search configuration with the options applied.
+func newSearchConfig(options ...SearchOption) *searchConfig {
+	cfg := &searchConfig{mode: MatchFuzzy, limit: 10, maxDist: -1}
+	for _, opt := range options {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// BusEntry represents a bus within the name index.
+type BusEntry struct {
+	Hnd  int
+	Name string
+	KV   float64
+}
+
+func (b BusEntry) String() string {
+	return fmt.Sprintf("%s %0.2f", b.Name, b.KV)
+}
+
+// BranchEntry represents a branch equipment within the name index. Three winding transformers have three bus handles.
+type BranchEntry struct {
+	Hnd     int // Equipment handle.
+	EqType  int
+	BusHnds []int
+	CktID   string
+}
+
+// BusCandidate represents a ranked bus search result, scores range from 0 to 1 for an exact match.
+type BusCandidate struct {
+	BusEntry
+	Score float64
+}
+
+// BranchCandidate represents a ranked branch search result, with the matched from and to buses.
+type BranchCandidate struct {
+	BranchEntry
+	From, To BusCandidate
+	Score    float64
+}
+
+func (b BranchCandidate) String() string {
+	return fmt.Sprintf("%s-%s ckt:%s", b.From.BusEntry, b.To.BusEntry, b.CktID)
+}
+
+// NameIndex represents a cached index of bus names and branches for repeated searches. Obtain for the loaded
+// case using Client.NameIndex, or construct using NewNameIndex.
+type NameIndex struct {
+	buses    []BusEntry
+	busByHnd map[int]int
+	branches []BranchEntry
+}
+
+// NewNameIndex returns a new name index for the provided buses and branches.
+func NewNameIndex(buses []BusEntry, branches []BranchEntry) *NameIndex {
+	idx := &NameIndex{
+		buses:    append([]BusEntry(nil), buses...),
+		busByHnd: make(map[int]int, len(buses)),
+		branches: append([]BranchEntry(nil), branches...),
+	}
+	for i, b := range idx.buses {
+		idx.busByHnd[b.Hnd] = i
+	}
+	return idx
+}
+
+// Bus returns the indexed bus with the provided handle.
+func (idx *NameIndex) Bus(hnd int) (BusEntry, bool) {
+	i, ok := idx.busByHnd[hnd]
+	if !ok {
+		return BusEntry{}, false
+	}
+	return idx.buses[i], true
+}
+
+// Len returns the number of buses within the index.
+func (idx *NameIndex) Len() int {
+	return len(idx.buses)
+}
+
+// indexBranchTypes lists the branch equipment types and their bus and circuit id tokens included in the name index.
+var indexBranchTypes = []struct {
+	eqType int
+	tokens []int // Bus handle tokens followed by the circuit id token.
+}{
+	{TCLine, []int{LNnBus1Hnd, LNnBus2Hnd, LNsID}},
+	{TCXFMR, []int{XRnBus1Hnd, XRnBus2Hnd, XRsID}},
+	{TCXFMR3, []int{X3nBus1Hnd, X3nBus2Hnd, X3nBus3Hnd, X3sID}},
+	{TCPS, []int{PSnBus1Hnd, PSnBus2Hnd, PSsID}},
+	{TCSCAP, []int{SCnBus1Hnd, SCnBus2Hnd, SCsID}},
+	{TCSwitch, []int{SWnBus1Hnd, SWnBus2Hnd, SWsID}},
+}
+
+// NameIndex returns the name index for the loaded case. The index is built on first use and cached until the
+// case is loaded, closed, changed using ReadChangeFile, equipment is deleted or indexed data is written using
+// SetData, including through a Tx. Call InvalidateNameIndex after renaming or adding equipment outside of this
+// package.
+func (c *Client) NameIndex() (*NameIndex, error) {
+	if c.nameIndex != nil {
+		return c.nameIndex, nil
+	}
+	var buses []BusEntry
+	for bi := c.NextEquipment(TCBus); bi.Next(); {
+		b := BusEntry{Hnd: bi.Hnd()}
+		if err := c.GetData(b.Hnd, BUSsName, BUSdKVnominal).Scan(&b.Name, &b.KV); err != nil {
+			return nil, fmt.Errorf("NameIndex: could not scan bus data %v", err)
+		}
+		buses = append(buses, b)
+	}
+	var branches []BranchEntry
+	for _, bt := range indexBranchTypes {
+		n := len(bt.tokens) - 1
+		for ei := c.NextEquipment(bt.eqType); ei.Next(); {
+			br := BranchEntry{Hnd: ei.Hnd(), EqType: bt.eqType, BusHnds: make([]int, n)}
+			dest := make([]interface{}, 0, n+1)
+			for i := range br.BusHnds {
+				dest = append(dest, &br.BusHnds[i])
+			}
+			dest = append(dest, &br.CktID)
+			if err := c.GetData(br.Hnd, bt.tokens...).Scan(dest...); err != nil {
+				return nil, fmt.Errorf("NameIndex: could not scan branch data %v", err)
+			}
+			branches = append(branches, br)
+		}
+	}
+	c.nameIndex = NewNameIndex(buses, branches)
+	return c.nameIndex, nil
+}
+
+// isNameIndexToken reports whether the token of the equipment type is bus or branch data held within the name
+// index. Token values are only unique within an equipment type.
+func isNameIndexToken(eqType, token int) bool {
+	if eqType == TCBus {
+		return token == BUSsName || token == BUSdKVnominal
+	}
+	for _, bt := range indexBranchTypes {
+		if bt.eqType == eqType {
+			return containsInt(bt.tokens, token)
+		}
+	}
+	return false
+}
+
+// InvalidateNameIndex clears the cached name index, it is rebuilt on next use.
+func (c *Client) InvalidateNameIndex() {
+	c.nameIndex = nil
+}
+
+// SearchBus searches the loaded case bus names, see NameIndex.SearchBus.
+func (c *Client) SearchBus(query string, options ...SearchOption) ([]BusCandidate, error) {
+	idx, err := c.NameIndex()
+	if err != nil {
+		return nil, err
+	}
+	return idx.SearchBus(query, options...)
+}
+
+// SearchBranch searches the loaded case branches by bus names, see NameIndex.SearchBranch.
+func (c *Client) SearchBranch(from, to string, options ...SearchOption) ([]BranchCandidate, error) {
+	idx, err := c.NameIndex()
+	if err != nil {
+		return nil, err
+	}
+	return idx.SearchBranch(from, to, options...)
+}
+
+// matcher returns a name scoring function for the query and match mode. Scores range from 0, no match, to 1
+// for an exact match.
+func matcher(query string, mode MatchMode, maxDist int) (func(name string) float64, error) {
+	q := strings.ToUpper(strings.TrimSpace(query))
+	if q == "" {
+		return nil, fmt.Errorf("empty query")
+	}
+	switch mode {
+	case MatchExact:
+		return func(name string) float64 {
+			if strings.ToUpper(strings.TrimSpace(name)) == q {
+				return 1
+			}
+			return 0
+		}, nil
+	case MatchPrefix:
+		return func(name string) float64 {
+			n := strings.ToUpper(strings.TrimSpace(name))
+			if n == "" || !strings.HasPrefix(n, q) {
+				return 0
+			}
+			return 0.5 + 0.5*float64(len(q))/float64(len(n))
+		}, nil
+	case MatchRegex:
+		re, err := regexp.Compile("(?i)" + query)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression: %v", err)
+		}
+		return func(name string) float64 {
+			n := strings.TrimSpace(name)
+			loc := re.FindStringIndex(n)
+			if loc == nil {
+				return 0
+			}
+			return 0.5 + 0.5*float64(loc[1]-loc[0])/float64(len(n))
+		}, nil
+	case MatchFuzzy:
+		qr := []rune(q)
+		if maxDist < 0 {
+			maxDist = len(qr) / 3
+			if maxDist < 1 {
+				maxDist = 1
+			}
+		}
+		return func(name string) float64 {
+			n := []rune(strings.ToUpper(strings.TrimSpace(name)))
+			var best float64
+			if d := levenshtein(qr, n); d <= maxDist {
+				best = 1 - float64(d)/float64(maxInt(len(qr), len(n)))
+			}
+			// Partial names are matched against the name prefix of the query length, scoring below full matches.
+			if len(n) > len(qr) {
+				if d := levenshtein(qr, n[:len(qr)]); d <= maxDist {
+					partial := 0.9 * (1 - float64(d)/float64(len(qr))) * (0.5 + 0.5*float64(len(qr))/float64(len(n)))
+					best = math.Max(best, partial)
+				}
+			}
+			return best
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown match mode %v", mode)
+}
+
+// maxInt returns the maximum of the provided integers.
+func maxInt(a, b int) int {
+	if b > a {
+		return b
+	}
+	return a
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = prev[j] + 1
+			if v := cur[j-1] + 1; v < cur[j] {
+				cur[j] = v
+			}
+			if v := prev[j-1] + cost; v < cur[j] {
+				cur[j] = v
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// kvWithin reports whether kv is within tol per unit of the target kV, always true if the target is zero.
+func kvWithin(kv, target, tol float64) bool {
+	return target == 0 || math.Abs(kv-target) <= tol*target
+}
+
+// scoreBuses returns the matching buses and their scores keyed by bus handle.
+func (idx *NameIndex) scoreBuses(query string, cfg *searchConfig, kv, tol float64) (map[int]BusCandidate, error) {
+	score, err := matcher(query, cfg.mode, cfg.maxDist)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[int]BusCandidate)
+	for _, b := range idx.buses {
+		if !kvWithin(b.KV, kv, tol) {
+			continue
+		}
+		if s := score(b.Name); s > 0 {
+			out[b.Hnd] = BusCandidate{BusEntry: b, Score: s}
+		}
+	}
+	return out, nil
+}
+
+// SearchBus returns bus candidates matching the query, ranked by descending score. By default fuzzy matching
+// is used, returning up to 10 candidates. See SearchOption for available options.
+func (idx *NameIndex) SearchBus(query string, options ...SearchOption) ([]BusCandidate, error) {
+	cfg := newSearchConfig(options...)
+	matches, err := idx.scoreBuses(query, cfg, cfg.kv, cfg.kvTol)
+	if err != nil {
+		return nil, fmt.Errorf("SearchBus: %v", err)
+	}
+	out := make([]BusCandidate, 0, len(matches))
+	for _, m := range matches {
+		out = append(out, m)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.KV > b.KV
+	})
+	if cfg.limit > 0 && len(out) > cfg.limit {
+		out = out[:cfg.limit]
+	}
+	return out, nil
+}
+
+// SearchBranch returns branch candidates between buses matching the from and to queries, in either direction,
+// ranked by descending score. Three winding transformers match on any two of their buses. The candidate score
+// is the mean of the from and to bus scores.
+func (idx *NameIndex) SearchBranch(from, to string, options ...SearchOption) ([]BranchCandidate, error) {
+	cfg := newSearchConfig(options...)
+	fromMatches, err := idx.scoreBuses(from, cfg, cfg.kv, cfg.kvTol)
+	if err != nil {
+		return nil, fmt.Errorf("SearchBranch: %v", err)
+	}
+	toMatches, err := idx.scoreBuses(to, cfg, cfg.toKV, cfg.toTol)
+	if err != nil {
+		return nil, fmt.Errorf("SearchBranch: %v", err)
+	}
+	ckt := strings.TrimSpace(cfg.ckt)
+
+	var out []BranchCandidate
+	for _, br := range idx.branches {
+		if ckt != "" && strings.TrimSpace(br.CktID) != ckt {
+			continue
+		}
+		var best *BranchCandidate
+		for i, fHnd := range br.BusHnds {
+			f, ok := fromMatches[fHnd]
+			if !ok {
+				continue
+			}
+			for j, tHnd := range br.BusHnds {
+				t, ok := toMatches[tHnd]
+				if !ok || i == j {
+					continue
+				}
+				if s := (f.Score + t.Score) / 2; best == nil || s > best.Score {
+					best = &BranchCandidate{BranchEntry: br, From: f, To: t, Score: s}
+				}
+			}
+		}
+		if best != nil {
+			out = append(out, *best)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.From.Name != b.From.Name {
+			return a.From.Name < b.From.Name
+		}
+		if a.To.Name != b.To.Name {
+			return a.To.Name < b.To.Name
+		}
+		return a.CktID < b.CktID
+	})
+	if cfg.limit > 0 && len(out) > cfg.limit {
+		out = out[:cfg.limit]
+	}
+	return out, nil
+}
@@ -0,0 +1,182 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"testing"
+)
+
+func testNameIndex() *NameIndex {
+	return NewNameIndex(
+		[]BusEntry{
+			{Hnd: 1, Name: "CLAYTOR", KV: 132},
+			{Hnd: 2, Name: "NEVADA", KV: 132},
+			{Hnd: 3, Name: "NEW HAMPSHR", KV: 33},
+			{Hnd: 4, Name: "NEVADA", KV: 33},
+			{Hnd: 5, Name: "OHIO", KV: 132},
+			{Hnd: 6, Name: "FIELDALE", KV: 132},
+			{Hnd: 7, Name: "NEW MEXICO", KV: 132},
+		},
+		[]BranchEntry{
+			{Hnd: 101, EqType: TCLine, BusHnds: []int{1, 2}, CktID: "1"},
+			{Hnd: 102, EqType: TCLine, BusHnds: []int{1, 2}, CktID: "2"},
+			{Hnd: 103, EqType: TCLine, BusHnds: []int{6, 5}, CktID: "1"},
+			{Hnd: 104, EqType: TCXFMR3, BusHnds: []int{2, 3, 4}, CktID: "1"},
+		},
+	)
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		d    int
+	}{
+		{"", "", 0},
+		{"NEVADA", "NEVADA", 0},
+		{"NEVDA", "NEVADA", 1},
+		{"CLAYTON", "CLAYTOR", 1},
+		{"KITTEN", "SITTING", 3},
+	}
+	for _, tt := range tests {
+		if got := levenshtein([]rune(tt.a), []rune(tt.b)); got != tt.d {
+			t.Errorf("%s-%s: expected %d, got %d", tt.a, tt.b, tt.d, got)
+		}
+	}
+}
+
+func TestNameIndex_SearchBus(t *testing.T) {
+	idx := testNameIndex()
+	tests := []struct {
+		name     string
+		query    string
+		options  []SearchOption
+		expected []int
+	}{
+		{name: "exact", query: "nevada", options: []SearchOption{SearchMatch(MatchExact)}, expected: []int{2, 4}},
+		{name: "exact kv", query: "nevada", options: []SearchOption{SearchMatch(MatchExact), SearchKV(33, 0.05)}, expected: []int{4}},
+		{name: "prefix", query: "new", options: []SearchOption{SearchMatch(MatchPrefix)}, expected: []int{7, 3}},
+		{name: "regex", query: "^n.*a$", options: []SearchOption{SearchMatch(MatchRegex)}, expected: []int{2, 4}},
+		{name: "fuzzy typo", query: "CLAYTON", expected: []int{1}},
+		{name: "fuzzy partial", query: "FIELDAL", expected: []int{6}},
+		{name: "fuzzy limit", query: "NEVDA", options: []SearchOption{SearchLimit(1)}, expected: []int{2}},
+		{name: "fuzzy distance", query: "OHAI", options: []SearchOption{SearchMaxDistance(1)}, expected: []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := idx.SearchBus(tt.query, tt.options...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, got)
+			}
+			for i, c := range got {
+				if c.Hnd != tt.expected[i] {
+					t.Errorf("expected %v, got %v", tt.expected, got)
+				}
+				if c.Score <= 0 || c.Score > 1 {
+					t.Errorf("score out of range %v", c.Score)
+				}
+			}
+		})
+	}
+
+	// Exact matches rank ahead of partial fuzzy matches.
+	got, err := idx.SearchBus("NEVADA", SearchKV(132, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) == 0 || got[0].Hnd != 2 || got[0].Score != 1 {
+		t.Errorf("expected exact match first, got %v", got)
+	}
+
+	if _, err := idx.SearchBus("(", SearchMatch(MatchRegex)); err == nil {
+		t.Error("expected error for invalid regex")
+	}
+	if _, err := idx.SearchBus(" "); err == nil {
+		t.Error("expected error for empty query")
+	}
+}
+
+func TestNameIndex_SearchBranch(t *testing.T) {
+	idx := testNameIndex()
+
+	got, err := idx.SearchBranch("NEVDA", "CLAYTOR", SearchKV(132, 0.05))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Hnd != 101 || got[1].Hnd != 102 {
+		t.Fatalf("expected parallel circuits 101 and 102, got %v", got)
+	}
+	if got[0].From.Hnd != 2 || got[0].To.Hnd != 1 {
+		t.Errorf("expected reversed orientation, got %v", got[0])
+	}
+
+	got, err = idx.SearchBranch("CLAYTOR", "NEVADA", SearchCktID("2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Hnd != 102 {
+		t.Errorf("expected circuit 2, got %v", got)
+	}
+
+	// Three winding transformers match on any two buses.
+	got, err = idx.SearchBranch("NEW HAMP", "NEVADA", SearchMatch(MatchPrefix), SearchToKV(33, 0.05))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Hnd != 104 || got[0].To.Hnd != 4 {
+		t.Errorf("expected xfmr3 104, got %v", got)
+	}
+
+	if b, ok := idx.Bus(5); !ok || b.Name != "OHIO" {
+		t.Errorf("unexpected bus %v", b)
+	}
+}
+
+func TestIsNameIndexToken(t *testing.T) {
+	tests := []struct {
+		eqType, token int
+		expected      bool
+	}{
+		{TCBus, BUSsName, true},
+		{TCBus, BUSdKVnominal, true},
+		{TCLine, LNsID, true},
+		{TCXFMR, XRnBus2Hnd, true},
+		{TCXFMR3, X3nBus3Hnd, true},
+		{TCSwitch, SWsID, true},
+		{TCBus, BUSnNumber, false},
+		{TCLine, LNdR, false},
+		{TCXFMR, XRdR, false},
+		{TCGen, GEnBusHnd, false},
+	}
+	for _, tt := range tests {
+		if got := isNameIndexToken(tt.eqType, tt.token); got != tt.expected {
+			t.Errorf("type %d token %d: expected %v, got %v", tt.eqType, tt.token, tt.expected, got)
+		}
+	}
+}
+
+func TestClient_SearchBus(t *testing.T) {
+	c := NewClient()
+	defer c.Release()
+	if err := c.LoadDataFile(testCase); err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.SearchBus("CLAYTON", SearchKV(132, 0.1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) == 0 || got[0].Name != "CLAYTOR" {
+		t.Errorf("expected CLAYTOR, got %v", got)
+	}
+	br, err := c.SearchBranch("FIELDAL", "OHI")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(br) == 0 || br[0].EqType != TCLine {
+		t.Errorf("expected FIELDALE-OHIO line, got %v", br)
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"fmt"
+	"math"
+)
+
+var sqrt3 = math.Sqrt(3)
+
+// Base represents the per unit base quantities at a bus. MVA is the three phase base power and KV the line to
+// line base voltage.
+type Base struct {
+	MVA float64
+	KV  float64
+}
+
+// Amps returns the base current in amps.
+func (b Base) Amps() float64 {
+	return b.MVA * 1000 / (sqrt3 * b.KV)
+}
+
+// Ohms returns the base impedance in ohms.
+func (b Base) Ohms() float64 {
+	return b.KV * b.KV / b.MVA
+}
+
+// Siemens returns the base admittance in siemens.
+func (b Base) Siemens() float64 {
+	return b.MVA / (b.KV * b.KV)
+}
+
+// CurrentToPU converts the current in amps to per unit.
+func (b Base) CurrentToPU(amps float64) float64 {
+	return amps / b.Amps()
+}
+
+// CurrentFromPU converts the per unit current to amps.
+func (b Base) CurrentFromPU(pu float64) float64 {
+	return pu * b.Amps()
+}
+
+// VoltageToPU converts the line to line voltage in kV to per unit.
+func (b Base) VoltageToPU(kV float64) float64 {
+	return kV / b.KV
+}
+
+// VoltageFromPU converts the per unit voltage to line to line kV.
+func (b Base) VoltageFromPU(pu float64) float64 {
+	return pu * b.KV
+}
+
+// PhaseVoltageToPU converts the line to neutral voltage in kV to per unit.
+func (b Base) PhaseVoltageToPU(kV float64) float64 {
+	return kV * sqrt3 / b.KV
+}
+
+// PhaseVoltageFromPU converts the per unit voltage to line to neutral kV.
+func (b Base) PhaseVoltageFromPU(pu float64) float64 {
+	return pu * b.KV / sqrt3
+}
+
+// ImpedanceToPU converts the impedance in ohms to per unit.
+func (b Base) ImpedanceToPU(ohms complex128) complex128 {
+	return ohms / complex(b.Ohms(), 0)
+}
+
+// ImpedanceFromPU converts the per unit impedance to ohms.
+func (b Base) ImpedanceFromPU(pu complex128) complex128 {
+	return pu * complex(b.Ohms(), 0)
+}
+
+// AdmittanceToPU converts the admittance in siemens to per unit.
+func (b Base) AdmittanceToPU(siemens complex128) complex128 {
+	return siemens / complex(b.Siemens(), 0)
+}
+
+// AdmittanceFromPU converts the per unit admittance to siemens.
+func (b Base) AdmittanceFromPU(pu complex128) complex128 {
+	return pu * complex(b.Siemens(), 0)
+}
+
+// CurrentPhasorToPU converts the current phasor in amps to per unit, e.g. GetSCCurrent results.
+func (b Base) CurrentPhasorToPU(p Phasor) Phasor {
+	return p / Phasor(complex(b.Amps(), 0))
+}
+
+// CurrentPhasorFromPU converts the per unit current phasor to amps.
+func (b Base) CurrentPhasorFromPU(p Phasor) Phasor {
+	return p * Phasor(complex(b.Amps(), 0))
+}
+
+// VoltagePhasorToPU converts the line to neutral voltage phasor in kV to per unit, e.g. GetSCVoltage results.
+func (b Base) VoltagePhasorToPU(p Phasor) Phasor {
+	return p * Phasor(complex(sqrt3/b.KV, 0))
+}
+
+// VoltagePhasorFromPU converts the per unit voltage phasor to line to neutral kV.
+func (b Base) VoltagePhasorFromPU(p Phasor) Phasor {
+	return p * Phasor(complex(b.KV/sqrt3, 0))
+}
+
+// ChangeBase converts the per unit impedance from one base to another.
+func ChangeBase(z complex128, from, to Base) complex128 {
+	return z * complex((from.KV*from.KV/from.MVA)/(to.KV*to.KV/to.MVA), 0)
+}
+
+// PerUnit represents the per unit base system of a case, with a common MVA base and bus nominal kV bases.
+// Obtain from a case using Client.PerUnit, or construct using NewPerUnit.
+type PerUnit struct {
+	BaseMVA float64
+	busKV   map[int]float64
+}
+
+// NewPerUnit returns a new per unit base system with the provided MVA base and bus nominal kV bases by bus handle.
+func NewPerUnit(baseMVA float64, busKV map[int]float64) *PerUnit {
+	pu := &PerUnit{BaseMVA: baseMVA, busKV: make(map[int]float64, len(busKV))}
+	for hnd, kv := range busKV {
+		pu.busKV[hnd] = kv
+	}
+	return pu
+}
+
+// PerUnit loads the case per unit base system from the system MVA base and bus nominal kV.
+func (c *Client) PerUnit() (*PerUnit, error) {
+	var baseMVA float64
+	if err := c.GetData(HNDSYS, SYdBaseMVA).Scan(&baseMVA); err != nil {
+		return nil, fmt.Errorf("PerUnit: could not get system base MVA: %v", err)
+	}
+	busKV := make(map[int]float64)
+	for bi := c.NextEquipment(TCBus); bi.Next(); {
+		var kv float64
+		if err := c.GetData(bi.Hnd(), BUSdKVnominal).Scan(&kv); err != nil {
+			return nil, fmt.Errorf("PerUnit: could not get bus nominal kV: %v", err)
+		}
+		busKV[bi.Hnd()] = kv
+	}
+	return &PerUnit{BaseMVA: baseMVA, busKV: busKV}, nil
+}
+
+// SetBusKV sets the bus nominal kV base.
+func (pu *PerUnit) SetBusKV(busHnd int, kv float64) {
+	if pu.busKV == nil {
+		pu.busKV = make(map[int]float64)
+	}
+	pu.busKV[busHnd] = kv
+}
+
+// Base returns the per unit base at the bus. Returns an error if the bus is unknown, or has no nominal kV.
+func (pu *PerUnit) Base(busHnd int) (Base, error) {
+	kv, ok := pu.busKV[busHnd]
+	if !ok {
+		return Base{}, fmt.Errorf("Base: unknown bus handle %d", busHnd)
+	}
+	if kv <= 0 || pu.BaseMVA <= 0 {
+		return Base{}, fmt.Errorf("Base: invalid base %0.2f MVA, %0.2f kV at bus handle %d", pu.BaseMVA, kv, busHnd)
+	}
+	return Base{MVA: pu.BaseMVA, KV: kv}, nil
+}
+
+// ratio returns the nominal kV ratio between the to and from buses.
+func (pu *PerUnit) ratio(fromBusHnd, toBusHnd int) (float64, error) {
+	from, err := pu.Base(fromBusHnd)
+	if err != nil {
+		return 0, err
+	}
+	to, err := pu.Base(toBusHnd)
+	if err != nil {
+		return 0, err
+	}
+	return to.KV / from.KV, nil
+}
+
+// ReferImpedance refers the impedance in ohms at the from bus to the to bus voltage level, e.g. across a
+// transformer, using the bus nominal kV ratio. Per unit impedances are unchanged across transformers.
+func (pu *PerUnit) ReferImpedance(fromBusHnd, toBusHnd int, ohms complex128) (complex128, error) {
+	n, err := pu.ratio(fromBusHnd, toBusHnd)
+	if err != nil {
+		return 0, fmt.Errorf("ReferImpedance: %v", err)
+	}
+	return ohms * complex(n*n, 0), nil
+}
+
+// ReferCurrent refers the current in amps at the from bus to the to bus voltage level using the bus nominal
+// kV ratio.
+func (pu *PerUnit) ReferCurrent(fromBusHnd, toBusHnd int, amps Phasor) (Phasor, error) {
+	n, err := pu.ratio(fromBusHnd, toBusHnd)
+	if err != nil {
+		return 0, fmt.Errorf("ReferCurrent: %v", err)
+	}
+	return amps / Phasor(complex(n, 0)), nil
+}
+
+// ReferVoltage refers the voltage in kV at the from bus to the to bus voltage level using the bus nominal
+// kV ratio.
+func (pu *PerUnit) ReferVoltage(fromBusHnd, toBusHnd int, kV Phasor) (Phasor, error) {
+	n, err := pu.ratio(fromBusHnd, toBusHnd)
+	if err != nil {
+		return 0, fmt.Errorf("ReferVoltage: %v", err)
+	}
+	return kV * Phasor(complex(n, 0)), nil
+}
@@ -0,0 +1,154 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+}
+
+func TestBase(t *testing.T) {
+	b := Base{MVA: 100, KV: 138}
+	tests := []struct {
+		name          string
+		got, expected float64
+	}{
+		{"Amps", b.Amps(), 418.36976},
+		{"Ohms", b.Ohms(), 190.44},
+		{"Siemens", b.Siemens(), 1 / 190.44},
+		{"CurrentToPU", b.CurrentToPU(836.73952), 2},
+		{"CurrentFromPU", b.CurrentFromPU(2), 836.73952},
+		{"VoltageToPU", b.VoltageToPU(144.9), 1.05},
+		{"VoltageFromPU", b.VoltageFromPU(1.05), 144.9},
+		{"PhaseVoltageToPU", b.PhaseVoltageToPU(138 / math.Sqrt(3)), 1},
+		{"PhaseVoltageFromPU", b.PhaseVoltageFromPU(1), 138 / math.Sqrt(3)},
+	}
+	for _, tt := range tests {
+		if math.Abs(tt.got-tt.expected) > 1e-4 {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, tt.got)
+		}
+	}
+
+	z := complex(19.044, 190.44)
+	if got := b.ImpedanceToPU(z); cmplx.Abs(got-complex(0.1, 1)) > 1e-12 {
+		t.Errorf("ImpedanceToPU: got %v", got)
+	}
+	if got := b.ImpedanceFromPU(b.ImpedanceToPU(z)); cmplx.Abs(got-z) > 1e-9 {
+		t.Errorf("ImpedanceFromPU: got %v", got)
+	}
+	y := complex(0, 0.001)
+	if got := b.AdmittanceToPU(y); cmplx.Abs(got-complex(0, 0.19044)) > 1e-12 {
+		t.Errorf("AdmittanceToPU: got %v", got)
+	}
+	if got := b.AdmittanceFromPU(b.AdmittanceToPU(y)); cmplx.Abs(got-y) > 1e-12 {
+		t.Errorf("AdmittanceFromPU: got %v", got)
+	}
+
+	i := NewPhasor(b.Amps()*3, -80)
+	if got := b.CurrentPhasorToPU(i); !almostEqual(got.Mag(), 3) || !almostEqual(got.Ang(), -80) {
+		t.Errorf("CurrentPhasorToPU: got %v", got)
+	}
+	if got := b.CurrentPhasorFromPU(b.CurrentPhasorToPU(i)); cmplx.Abs(complex128(got-i)) > 1e-9 {
+		t.Errorf("CurrentPhasorFromPU: got %v", got)
+	}
+	v := NewPhasor(138/math.Sqrt(3)*0.95, 10)
+	if got := b.VoltagePhasorToPU(v); !almostEqual(got.Mag(), 0.95) || !almostEqual(got.Ang(), 10) {
+		t.Errorf("VoltagePhasorToPU: got %v", got)
+	}
+	if got := b.VoltagePhasorFromPU(b.VoltagePhasorToPU(v)); cmplx.Abs(complex128(got-v)) > 1e-9 {
+		t.Errorf("VoltagePhasorFromPU: got %v", got)
+	}
+}
+
+func TestChangeBase(t *testing.T) {
+	got := ChangeBase(complex(0, 0.1), Base{MVA: 50, KV: 13.8}, Base{MVA: 100, KV: 13.8})
+	if cmplx.Abs(got-complex(0, 0.2)) > 1e-12 {
+		t.Errorf("expected 0.2j, got %v", got)
+	}
+	got = ChangeBase(complex(0, 0.1), Base{MVA: 100, KV: 12.47}, Base{MVA: 100, KV: 13.8})
+	if expected := 0.1 * (12.47 * 12.47) / (13.8 * 13.8); !almostEqual(imag(got), expected) {
+		t.Errorf("expected %vj, got %v", expected, got)
+	}
+}
+
+func TestPerUnit(t *testing.T) {
+	pu := NewPerUnit(100, map[int]float64{1: 138, 2: 13.8})
+	pu.SetBusKV(3, 0)
+
+	if _, err := pu.Base(4); err == nil {
+		t.Errorf("expected error for unknown bus")
+	}
+	if _, err := pu.Base(3); err == nil {
+		t.Errorf("expected error for zero kV base")
+	}
+
+	b1, err := pu.Base(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b2, err := pu.Base(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Ohms referred across a transformer have the same per unit value on both sides.
+	z1 := complex(19.044, 190.44)
+	z2, err := pu.ReferImpedance(1, 2, z1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmplx.Abs(b1.ImpedanceToPU(z1)-b2.ImpedanceToPU(z2)) > 1e-12 {
+		t.Errorf("ReferImpedance: per unit mismatch %v, %v", b1.ImpedanceToPU(z1), b2.ImpedanceToPU(z2))
+	}
+
+	i1 := NewPhasor(100, -30)
+	i2, err := pu.ReferCurrent(1, 2, i1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !almostEqual(i2.Mag(), 1000) || !almostEqual(b1.CurrentPhasorToPU(i1).Mag(), b2.CurrentPhasorToPU(i2).Mag()) {
+		t.Errorf("ReferCurrent: got %v", i2)
+	}
+
+	v1 := NewPhasor(79.67, 0)
+	v2, err := pu.ReferVoltage(1, 2, v1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !almostEqual(v2.Mag(), 7.967) {
+		t.Errorf("ReferVoltage: got %v", v2)
+	}
+
+	if _, err := pu.ReferCurrent(1, 4, i1); err == nil {
+		t.Errorf("expected error for unknown bus")
+	}
+}
+
+func TestClient_PerUnit(t *testing.T) {
+	c := NewClient()
+	defer c.Release()
+	if err := c.LoadDataFile(testCase); err != nil {
+		t.Fatal(err)
+	}
+	pu, err := c.PerUnit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pu.BaseMVA != 100 {
+		t.Errorf("expected 100 MVA base, got %v", pu.BaseMVA)
+	}
+	bi := c.NextEquipment(TCBus)
+	if !bi.Next() {
+		t.Fatal("no buses in test case")
+	}
+	if _, err := pu.Base(bi.Hnd()); err != nil {
+		t.Error(err)
+	}
+}
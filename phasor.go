@@ -5,9 +5,13 @@
 package goolx
 
 import (
+	"encoding/json"
 	"fmt"
 	"math"
 	"math/cmplx"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 )
 
 var (
@@ -63,3 +67,288 @@ func (p Phasor) Rect() complex128 {
 func (p Phasor) String() string {
 	return fmt.Sprintf("%0.2f\u2220%0.1f\u00B0", p.Mag(), p.Ang())
 }
+
+// Format implements the fmt.Formatter interface. The %v and %s verbs format the phasor in polar form, with the
+// precision applied to both magnitude and angle, defaulting to the String form. The %q verb quotes the polar form.
+// The %e, %f and %g verbs format the phasor in rectangular form as for complex128, e.g. (1.00+2.00i). Width pads
+// the polar form.
+func (p Phasor) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'e', 'E', 'f', 'F', 'g', 'G':
+		fmt.Fprintf(f, formatString(f, verb), complex128(p))
+	case 'v', 's', 'q':
+		s := p.String()
+		if prec, ok := f.Precision(); ok {
+			s = fmt.Sprintf("%0.*f∠%0.*f°", prec, p.Mag(), prec, p.Ang())
+		}
+		if verb == 'q' {
+			s = strconv.Quote(s)
+		}
+		if width, ok := f.Width(); ok && len([]rune(s)) < width {
+			pad := strings.Repeat(" ", width-len([]rune(s)))
+			if f.Flag('-') {
+				s += pad
+			} else {
+				s = pad + s
+			}
+		}
+		fmt.Fprint(f, s)
+	default:
+		fmt.Fprintf(f, "%%!%c(Phasor=%s)", verb, p.String())
+	}
+}
+
+// formatString returns the format directive of the state and verb.
+func formatString(f fmt.State, verb rune) string {
+	var b strings.Builder
+	b.WriteByte('%')
+	for _, flag := range "+-# 0" {
+		if f.Flag(int(flag)) {
+			b.WriteRune(flag)
+		}
+	}
+	if width, ok := f.Width(); ok {
+		b.WriteString(strconv.Itoa(width))
+	}
+	if prec, ok := f.Precision(); ok {
+		b.WriteByte('.')
+		b.WriteString(strconv.Itoa(prec))
+	}
+	b.WriteRune(verb)
+	return b.String()
+}
+
+// ParsePhasor parses a phasor in polar form, e.g. "1.2∠-30°", "1.2∠-30" or "1.2<-30", or in rectangular form, e.g.
+// "1+2i", "(1+2i)" or "1+2j". Polar angles are in degrees.
+func ParsePhasor(s string) (Phasor, error) {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexAny(s, "∠<"); i >= 0 {
+		mag, err := strconv.ParseFloat(strings.TrimSpace(s[:i]), 64)
+		if err != nil {
+			return 0, fmt.Errorf("ParsePhasor: invalid magnitude %q", s)
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		ang := strings.TrimSuffix(strings.TrimSpace(s[i+size:]), "°")
+		a, err := strconv.ParseFloat(strings.TrimSpace(ang), 64)
+		if err != nil {
+			return 0, fmt.Errorf("ParsePhasor: invalid angle %q", s)
+		}
+		return NewPhasor(mag, a), nil
+	}
+	if strings.HasSuffix(s, "j") {
+		s = strings.TrimSuffix(s, "j") + "i"
+	} else if strings.HasSuffix(s, "j)") {
+		s = strings.TrimSuffix(s, "j)") + "i)"
+	}
+	c, err := strconv.ParseComplex(s, 128)
+	if err != nil {
+		return 0, fmt.Errorf("ParsePhasor: invalid phasor %q", s)
+	}
+	return Phasor(c), nil
+}
+
+// polarDigits is the number of significant digits of encoded polar values, dropping the rounding noise of the
+// rectangular to polar conversion.
+const polarDigits = 15
+
+// polarRound rounds v to polarDigits significant digits.
+func polarRound(v float64) float64 {
+	r, _ := strconv.ParseFloat(strconv.FormatFloat(v, 'g', polarDigits, 64), 64)
+	return r
+}
+
+// MarshalText implements the encoding.TextMarshaler interface, encoding the phasor in polar form, e.g. 1.2∠-30°.
+func (p Phasor) MarshalText() ([]byte, error) {
+	mag := strconv.FormatFloat(polarRound(p.Mag()), 'g', -1, 64)
+	ang := strconv.FormatFloat(polarRound(p.Ang()), 'g', -1, 64)
+	return []byte(mag + "∠" + ang + "°"), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, accepting any form parsed by ParsePhasor.
+func (p *Phasor) UnmarshalText(b []byte) error {
+	v, err := ParsePhasor(string(b))
+	if err != nil {
+		return err
+	}
+	*p = v
+	return nil
+}
+
+// polarJSON represents a phasor in polar form for JSON encoding.
+type polarJSON struct {
+	Mag float64 `json:"mag"`
+	Ang float64 `json:"ang"`
+}
+
+// rectJSON represents a phasor in rectangular form for JSON encoding.
+type rectJSON struct {
+	Re float64 `json:"re"`
+	Im float64 `json:"im"`
+}
+
+// MarshalJSON implements the json.Marshaler interface, encoding the phasor in polar form, e.g.
+// {"mag":1.2,"ang":-30}. See RectPhasor for rectangular form.
+func (p Phasor) MarshalJSON() ([]byte, error) {
+	return json.Marshal(polarJSON{polarRound(p.Mag()), polarRound(p.Ang())})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, accepting polar {"mag","ang"} or rectangular {"re","im"}
+// objects, or strings in any form parsed by ParsePhasor.
+func (p *Phasor) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		return p.UnmarshalText([]byte(s))
+	}
+	var v struct {
+		Mag *float64 `json:"mag"`
+		Ang *float64 `json:"ang"`
+		Re  *float64 `json:"re"`
+		Im  *float64 `json:"im"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	switch {
+	case v.Mag != nil && v.Re == nil && v.Im == nil:
+		var ang float64
+		if v.Ang != nil {
+			ang = *v.Ang
+		}
+		*p = NewPhasor(*v.Mag, ang)
+	case v.Mag == nil && v.Ang == nil && (v.Re != nil || v.Im != nil):
+		var re, im float64
+		if v.Re != nil {
+			re = *v.Re
+		}
+		if v.Im != nil {
+			im = *v.Im
+		}
+		*p = Phasor(complex(re, im))
+	default:
+		return fmt.Errorf("Phasor: invalid JSON phasor %s", b)
+	}
+	return nil
+}
+
+// RectPhasor represents a phasor encoded in rectangular form, e.g. {"re":1,"im":2} in JSON and (1+2i) in text.
+type RectPhasor Phasor
+
+// MarshalJSON implements the json.Marshaler interface.
+func (p RectPhasor) MarshalJSON() ([]byte, error) {
+	return json.Marshal(rectJSON{real(p), imag(p)})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, see Phasor.UnmarshalJSON.
+func (p *RectPhasor) UnmarshalJSON(b []byte) error {
+	return (*Phasor)(p).UnmarshalJSON(b)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (p RectPhasor) MarshalText() ([]byte, error) {
+	return []byte(strconv.FormatComplex(complex128(p), 'g', -1, 128)), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, see ParsePhasor.
+func (p *RectPhasor) UnmarshalText(b []byte) error {
+	return (*Phasor)(p).UnmarshalText(b)
+}
+
+// PhaseSet represents a three phase quantity in phase form, A, B and C.
+type PhaseSet [3]Phasor
+
+// SeqSet represents a three phase quantity in sequence form, zero, positive and negative.
+type SeqSet [3]Phasor
+
+// Seq returns the sequence components of the phase quantity.
+func (p PhaseSet) Seq() SeqSet {
+	var s SeqSet
+	s[0], s[1], s[2] = PhaseToSeq(p[0], p[1], p[2])
+	return s
+}
+
+// Mags returns the phase magnitudes.
+func (p PhaseSet) Mags() [3]float64 {
+	return [3]float64{p[0].Mag(), p[1].Mag(), p[2].Mag()}
+}
+
+// Unbalance returns the phase magnitude unbalance, the maximum deviation from the average magnitude relative to the
+// average magnitude (NEMA MG 1 definition). Zero quantities return 0.
+func (p PhaseSet) Unbalance() float64 {
+	m := p.Mags()
+	avg := (m[0] + m[1] + m[2]) / 3
+	if avg == 0 {
+		return 0
+	}
+	var dev float64
+	for _, v := range m {
+		dev = math.Max(dev, math.Abs(v-avg))
+	}
+	return dev / avg
+}
+
+// PhaseRotation represents the phase rotation of a three phase quantity.
+type PhaseRotation int
+
+// Phase rotations, see PhaseSet.Rotation.
+const (
+	RotationNone PhaseRotation = iota // No positive or negative sequence component.
+	RotationABC
+	RotationACB
+)
+
+func (r PhaseRotation) String() string {
+	switch r {
+	case RotationABC:
+		return "ABC"
+	case RotationACB:
+		return "ACB"
+	}
+	return "None"
+}
+
+// Rotation returns the phase rotation of the quantity, ABC where the positive sequence component exceeds the
+// negative sequence component.
+func (p PhaseSet) Rotation() PhaseRotation {
+	return p.Seq().Rotation()
+}
+
+// Phase returns the phase values of the sequence quantity.
+func (s SeqSet) Phase() PhaseSet {
+	var p PhaseSet
+	p[0], p[1], p[2] = SeqToPhase(s[0], s[1], s[2])
+	return p
+}
+
+// NegativeRatio returns the negative to positive sequence magnitude ratio. A quantity without positive sequence
+// returns +Inf, or 0 if it has no negative sequence either.
+func (s SeqSet) NegativeRatio() float64 {
+	return seqRatio(s[2], s[1])
+}
+
+// ZeroRatio returns the zero to positive sequence magnitude ratio, see NegativeRatio.
+func (s SeqSet) ZeroRatio() float64 {
+	return seqRatio(s[0], s[1])
+}
+
+// seqRatio returns the magnitude ratio of a to b.
+func seqRatio(a, b Phasor) float64 {
+	if b.Mag() == 0 {
+		if a.Mag() == 0 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	return a.Mag() / b.Mag()
+}
+
+// Rotation returns the phase rotation of the quantity, see PhaseSet.Rotation.
+func (s SeqSet) Rotation() PhaseRotation {
+	p, n := s[1].Mag(), s[2].Mag()
+	switch {
+	case p == 0 && n == 0:
+		return RotationNone
+	case p >= n:
+		return RotationABC
+	}
+	return RotationACB
+}
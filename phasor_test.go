@@ -4,7 +4,12 @@
 
 package goolx
 
-import "testing"
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+)
 
 func Test_PhaseToSeq(t *testing.T) {
 	va := NewPhasor(0, 0)
@@ -22,3 +27,194 @@ func Test_PhaseToSeq(t *testing.T) {
 		t.Errorf("expected %s, got %s", vc, vcCalc)
 	}
 }
+
+func TestParsePhasor(t *testing.T) {
+	tests := []struct {
+		s    string
+		want Phasor
+	}{
+		{"1.2∠-30°", NewPhasor(1.2, -30)},
+		{" 1.2 ∠ -30 ", NewPhasor(1.2, -30)},
+		{"1.2<-30", NewPhasor(1.2, -30)},
+		{"5∠90°", NewPhasor(5, 90)},
+		{"1+2i", Phasor(complex(1, 2))},
+		{"(1-2i)", Phasor(complex(1, -2))},
+		{"1+2j", Phasor(complex(1, 2))},
+		{"(3-4j)", Phasor(complex(3, -4))},
+		{"7", Phasor(complex(7, 0))},
+	}
+	for _, tt := range tests {
+		got, err := ParsePhasor(tt.s)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.s, err)
+			continue
+		}
+		if (got - tt.want).Mag() > 1e-9 {
+			t.Errorf("%q: expected %v, got %v", tt.s, tt.want, got)
+		}
+	}
+	for _, s := range []string{"", "abc", "1.2∠", "∠30", "1.2∠x°"} {
+		if _, err := ParsePhasor(s); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+}
+
+func TestPhasor_Format(t *testing.T) {
+	p := NewPhasor(1.23456, -30)
+	tests := []struct {
+		format, want string
+	}{
+		{"%v", "1.23∠-30.0°"},
+		{"%s", "1.23∠-30.0°"},
+		{"%.3v", "1.235∠-30.000°"},
+		{"%14v", "   1.23∠-30.0°"},
+		{"%-14v|", "1.23∠-30.0°   |"},
+		{"%q", `"1.23∠-30.0°"`},
+		{"%.2f", "(1.07-0.62i)"},
+		{"%d", "%!d(Phasor=1.23∠-30.0°)"},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprintf(tt.format, p); got != tt.want {
+			t.Errorf("%q: expected %q, got %q", tt.format, tt.want, got)
+		}
+	}
+}
+
+func TestPhasor_JSON(t *testing.T) {
+	p := NewPhasor(1.2, -30)
+	b, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"mag":1.2,"ang":-30}` {
+		t.Errorf("expected %s, got %s", `{"mag":1.2,"ang":-30}`, b)
+	}
+	var got Phasor
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if (got - p).Mag() > 1e-9 {
+		t.Errorf("expected %v, got %v", p, got)
+	}
+
+	tests := []struct {
+		js   string
+		want Phasor
+	}{
+		{`{"mag":2,"ang":90}`, NewPhasor(2, 90)},
+		{`{"mag":2}`, NewPhasor(2, 0)},
+		{`{"re":1,"im":-1}`, Phasor(complex(1, -1))},
+		{`"1.2∠-30°"`, NewPhasor(1.2, -30)},
+		{`"1+2i"`, Phasor(complex(1, 2))},
+	}
+	for _, tt := range tests {
+		var got Phasor
+		if err := json.Unmarshal([]byte(tt.js), &got); err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.js, err)
+			continue
+		}
+		if (got - tt.want).Mag() > 1e-9 {
+			t.Errorf("%s: expected %v, got %v", tt.js, tt.want, got)
+		}
+	}
+	for _, js := range []string{`{"mag":1,"re":1}`, `{}`, `"abc"`, `[1,2]`} {
+		var got Phasor
+		if err := json.Unmarshal([]byte(js), &got); err == nil {
+			t.Errorf("%s: expected error", js)
+		}
+	}
+}
+
+func TestRectPhasor(t *testing.T) {
+	p := RectPhasor(complex(1, -2))
+	b, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"re":1,"im":-2}` {
+		t.Errorf("expected %s, got %s", `{"re":1,"im":-2}`, b)
+	}
+	var got RectPhasor
+	if err := json.Unmarshal(b, &got); err != nil || got != p {
+		t.Errorf("expected %v, got %v %v", p, got, err)
+	}
+	text, _ := p.MarshalText()
+	if string(text) != "(1-2i)" {
+		t.Errorf("expected %q, got %q", "(1-2i)", text)
+	}
+	if err := got.UnmarshalText(text); err != nil || got != p {
+		t.Errorf("expected %v, got %v %v", p, got, err)
+	}
+}
+
+func TestPhasor_Text(t *testing.T) {
+	p := NewPhasor(1.5, 45)
+	b, err := p.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "1.5∠45°" {
+		t.Errorf("expected %q, got %q", "1.5∠45°", b)
+	}
+	var got Phasor
+	if err := got.UnmarshalText(b); err != nil {
+		t.Fatal(err)
+	}
+	if (got - p).Mag() > 1e-9 {
+		t.Errorf("expected %v, got %v", p, got)
+	}
+}
+
+func TestPhaseSet(t *testing.T) {
+	balanced := PhaseSet{NewPhasor(1, 0), NewPhasor(1, -120), NewPhasor(1, 120)}
+	seq := balanced.Seq()
+	if !almostEqual(seq[1].Mag(), 1) || seq[0].Mag() > 1e-9 || seq[2].Mag() > 1e-9 {
+		t.Errorf("expected positive sequence only, got %v", seq)
+	}
+	back := seq.Phase()
+	for i := range back {
+		if (back[i] - balanced[i]).Mag() > 1e-9 {
+			t.Errorf("phase %d: expected %v, got %v", i, balanced[i], back[i])
+		}
+	}
+	if balanced.Unbalance() > 1e-9 || seq.NegativeRatio() > 1e-9 {
+		t.Errorf("expected balanced, got unbalance %g negative ratio %g", balanced.Unbalance(), seq.NegativeRatio())
+	}
+	if r := balanced.Rotation(); r != RotationABC {
+		t.Errorf("expected %s, got %s", RotationABC, r)
+	}
+
+	acb := PhaseSet{balanced[0], balanced[2], balanced[1]}
+	if r := acb.Rotation(); r != RotationACB {
+		t.Errorf("expected %s, got %s", RotationACB, r)
+	}
+	if r := (PhaseSet{}).Rotation(); r != RotationNone {
+		t.Errorf("expected %s, got %s", RotationNone, r)
+	}
+
+	// NEMA example, 460 467 450 V: average 459, maximum deviation 9.
+	unbal := PhaseSet{NewPhasor(460, 0), NewPhasor(467, -120), NewPhasor(450, 120)}
+	if u := unbal.Unbalance(); !almostEqual(u, 9.0/459.0) {
+		t.Errorf("expected %g, got %g", 9.0/459.0, u)
+	}
+	if (PhaseSet{}).Unbalance() != 0 {
+		t.Errorf("expected zero unbalance for zero quantity")
+	}
+}
+
+func TestSeqSet_Ratios(t *testing.T) {
+	s := SeqSet{NewPhasor(0.5, 0), NewPhasor(2, 0), NewPhasor(1, 0)}
+	if r := s.NegativeRatio(); !almostEqual(r, 0.5) {
+		t.Errorf("expected 0.5, got %g", r)
+	}
+	if r := s.ZeroRatio(); !almostEqual(r, 0.25) {
+		t.Errorf("expected 0.25, got %g", r)
+	}
+	if r := (SeqSet{0, 0, 1}).NegativeRatio(); !math.IsInf(r, 1) {
+		t.Errorf("expected +Inf, got %g", r)
+	}
+	if r := (SeqSet{}).NegativeRatio(); r != 0 {
+		t.Errorf("expected 0, got %g", r)
+	}
+}
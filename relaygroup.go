@@ -0,0 +1,206 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RelayDevice represents a protective device or logic scheme within a relay group.
+type RelayDevice struct {
+	Hnd    int
+	EqType int
+	ID     string
+}
+
+func (d RelayDevice) String() string {
+	return d.ID
+}
+
+// relayIDTokens maps protective device equipment types to their id token.
+var relayIDTokens = map[int]int{
+	TCRLYOCG:  OGsID,
+	TCRLYOCP:  OPsID,
+	TCRLYDSG:  DGsID,
+	TCRLYDSP:  DPsID,
+	TCRLYD:    RDsID,
+	TCRLYV:    RVsID,
+	TCFuse:    FSsID,
+	TCRECLSRP: CPsID,
+	TCRECLSRG: CGsID,
+	TCScheme:  LSsID,
+}
+
+// RelayGroup represents a relay group data object, with its protective devices, logic schemes and the
+// protected branch end.
+type RelayGroup struct {
+	Hnd         int
+	Note        string
+	InService   int
+	Ops         int
+	BreakerTime float64
+	RecloseInt  []float64
+
+	// Protected branch end.
+	BranchHnd int // Branch handle, TCBranch.
+	BusHnd    int // Bus at the protected branch end.
+	EqHnd     int // Protected branch equipment handle.
+	EqType    int // Protected branch equipment type.
+
+	// Related relay groups and logic.
+	PrimaryHnd   int
+	BackupHnd    int
+	TripLogicHnd int
+	ReclLogicHnd int
+
+	// Protective devices by type.
+	OC           []RelayDevice // Phase and ground overcurrent relays.
+	Distance     []RelayDevice // Phase and ground distance relays.
+	Differential []RelayDevice
+	Voltage      []RelayDevice
+	Fuse         []RelayDevice
+	Recloser     []RelayDevice // Phase and ground reclosers.
+	Schemes      []RelayDevice // Logic schemes.
+}
+
+func (rg *RelayGroup) String() string {
+	return fmt.Sprintf("relay group %d devices:%d schemes:%d", rg.Hnd, len(rg.Devices()), len(rg.Schemes))
+}
+
+// Devices returns all protective devices within the relay group.
+func (rg *RelayGroup) Devices() []RelayDevice {
+	var devices []RelayDevice
+	for _, d := range [][]RelayDevice{rg.OC, rg.Distance, rg.Differential, rg.Voltage, rg.Fuse, rg.Recloser} {
+		devices = append(devices, d...)
+	}
+	return devices
+}
+
+// add adds the device to the typed device list.
+func (rg *RelayGroup) add(d RelayDevice) {
+	switch d.EqType {
+	case TCRLYOCG, TCRLYOCP:
+		rg.OC = append(rg.OC, d)
+	case TCRLYDSG, TCRLYDSP:
+		rg.Distance = append(rg.Distance, d)
+	case TCRLYD:
+		rg.Differential = append(rg.Differential, d)
+	case TCRLYV:
+		rg.Voltage = append(rg.Voltage, d)
+	case TCFuse:
+		rg.Fuse = append(rg.Fuse, d)
+	case TCRECLSRP, TCRECLSRG:
+		rg.Recloser = append(rg.Recloser, d)
+	}
+}
+
+// relayDevice loads the device type and id for the provided handle.
+func (c *Client) relayDevice(hnd int) (RelayDevice, error) {
+	d := RelayDevice{Hnd: hnd}
+	eqType, err := c.EquipmentType(hnd)
+	if err != nil {
+		return d, err
+	}
+	d.EqType = eqType
+	if tkn, ok := relayIDTokens[eqType]; ok {
+		if err := c.GetData(hnd, tkn).Scan(&d.ID); err != nil {
+			return d, err
+		}
+		d.ID = strings.TrimSpace(d.ID)
+	}
+	return d, nil
+}
+
+// GetRelayGroup loads the relay group data at the provided handle into a new RelayGroup object, including its
+// protective devices, logic schemes and protected branch. Returns error if the handle provided does not point to
+// an equipment type TCRLYGroup.
+func (c *Client) GetRelayGroup(hnd int) (*RelayGroup, error) {
+	if eqType, _ := c.EquipmentType(hnd); eqType != TCRLYGroup {
+		return nil, fmt.Errorf("GetRelayGroup: equipment type must be TCRLYGroup")
+	}
+	var rg = RelayGroup{Hnd: hnd}
+	data := c.GetData(hnd,
+		RGsNote,
+		RGnInService,
+		RGnOps,
+		RGdBreakerTime,
+		RGnBranchHnd,
+	)
+	if err := data.Scan(
+		&rg.Note,
+		&rg.InService,
+		&rg.Ops,
+		&rg.BreakerTime,
+		&rg.BranchHnd,
+	); err != nil {
+		return nil, fmt.Errorf("GetRelayGroup: could not scan relay group data %v", err)
+	}
+
+	// Ignoring errors on optional data, OlxAPI throws error if not present, we can default to zero value.
+	c.GetData(hnd, RGvdRecloseInt).Scan(&rg.RecloseInt)
+	c.GetData(hnd, RGnPrimaryHnd).Scan(&rg.PrimaryHnd)
+	c.GetData(hnd, RGnBackupHnd).Scan(&rg.BackupHnd)
+	c.GetData(hnd, RGnTripLogicHnd).Scan(&rg.TripLogicHnd)
+	c.GetData(hnd, RGnReclLogicHnd).Scan(&rg.ReclLogicHnd)
+
+	// Protected branch end.
+	if err := c.GetData(rg.BranchHnd, BRnBus1Hnd, BRnHandle).Scan(&rg.BusHnd, &rg.EqHnd); err != nil {
+		return nil, fmt.Errorf("GetRelayGroup: could not scan branch data %v", err)
+	}
+	rg.EqType, _ = c.EquipmentType(rg.EqHnd)
+
+	for ri := c.NextRelay(hnd); ri.Next(); {
+		d, err := c.relayDevice(ri.Hnd())
+		if err != nil {
+			return nil, fmt.Errorf("GetRelayGroup: %v", err)
+		}
+		rg.add(d)
+	}
+	for si := c.NextLogicScheme(hnd); si.Next(); {
+		d, err := c.relayDevice(si.Hnd())
+		if err != nil {
+			return nil, fmt.Errorf("GetRelayGroup: %v", err)
+		}
+		rg.Schemes = append(rg.Schemes, d)
+	}
+	return &rg, nil
+}
+
+// ProtectionMap represents the relay groups protecting each branch, keyed by branch equipment handle.
+type ProtectionMap map[int][]*RelayGroup
+
+// Groups returns the relay groups protecting the branch equipment, ordered by bus handle.
+func (m ProtectionMap) Groups(eqHnd int) []*RelayGroup {
+	return m[eqHnd]
+}
+
+// Unprotected returns the provided branch equipment handles without any relay groups.
+func (m ProtectionMap) Unprotected(eqHnds ...int) []int {
+	var out []int
+	for _, hnd := range eqHnds {
+		if len(m[hnd]) == 0 {
+			out = append(out, hnd)
+		}
+	}
+	return out
+}
+
+// ProtectionMap loads all relay groups in the case, returning the relay groups protecting each branch.
+func (c *Client) ProtectionMap() (ProtectionMap, error) {
+	m := make(ProtectionMap)
+	for gi := c.NextEquipment(TCRLYGroup); gi.Next(); {
+		rg, err := c.GetRelayGroup(gi.Hnd())
+		if err != nil {
+			return nil, fmt.Errorf("ProtectionMap: %v", err)
+		}
+		m[rg.EqHnd] = append(m[rg.EqHnd], rg)
+	}
+	for _, groups := range m {
+		sort.SliceStable(groups, func(i, j int) bool { return groups[i].BusHnd < groups[j].BusHnd })
+	}
+	return m, nil
+}
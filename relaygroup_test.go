@@ -0,0 +1,55 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"testing"
+)
+
+func TestRelayGroup_add(t *testing.T) {
+	var rg RelayGroup
+	for _, eqType := range []int{TCRLYOCG, TCRLYOCP, TCRLYDSG, TCRLYDSP, TCRLYD, TCRLYV, TCFuse, TCRECLSRP, TCRECLSRG, TCScheme} {
+		rg.add(RelayDevice{EqType: eqType})
+	}
+	counts := map[string]int{
+		"OC":           len(rg.OC),
+		"Distance":     len(rg.Distance),
+		"Differential": len(rg.Differential),
+		"Voltage":      len(rg.Voltage),
+		"Fuse":         len(rg.Fuse),
+		"Recloser":     len(rg.Recloser),
+	}
+	expected := map[string]int{"OC": 2, "Distance": 2, "Differential": 1, "Voltage": 1, "Fuse": 1, "Recloser": 2}
+	for k, v := range expected {
+		if counts[k] != v {
+			t.Errorf("%s: expected %d, got %d", k, v, counts[k])
+		}
+	}
+	if got := len(rg.Devices()); got != 9 {
+		t.Errorf("expected 9 devices, got %d", got)
+	}
+}
+
+func TestClient_ProtectionMap(t *testing.T) {
+	c := NewClient()
+	defer c.Release()
+	if err := c.LoadDataFile(testCase); err != nil {
+		t.Fatal(err)
+	}
+	m, err := c.ProtectionMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m) == 0 {
+		t.Fatal("expected protected branches in test case")
+	}
+	for eqHnd, groups := range m {
+		for _, rg := range groups {
+			if rg.EqHnd != eqHnd || rg.BusHnd == 0 {
+				t.Errorf("unexpected protected branch end for %v", rg)
+			}
+		}
+	}
+}
@@ -9,87 +9,194 @@ import (
 	"strings"
 )
 
-// FindLine searches for a branch with the given branch data, returns the branch handle.
+// cktIDTokens maps branch equipment types to their circuit id token.
+var cktIDTokens = map[int]int{
+	TCLine:   LNsID,
+	TCXFMR:   XRsID,
+	TCXFMR3:  X3sID,
+	TCPS:     PSsID,
+	TCSCAP:   SCsID,
+	TCSwitch: SWsID,
+}
+
+// Branch represents a branch between two buses, as found by BranchesBetween.
+type Branch struct {
+	Hnd     int // Branch handle, TCBranch.
+	EqHnd   int // Branch equipment handle.
+	EqType  int
+	CktID   string
+	FromHnd int // From bus handle.
+	ToHnd   int // To bus handle.
+}
+
+func (b Branch) String() string {
+	return fmt.Sprintf("%s ckt:%s", equipmentTypeName(b.EqType), b.CktID)
+}
+
+// equipmentTypeName returns a short name for branch equipment types.
+func equipmentTypeName(eqType int) string {
+	switch eqType {
+	case TCLine:
+		return "TCLine"
+	case TCXFMR:
+		return "TCXFMR"
+	case TCXFMR3:
+		return "TCXFMR3"
+	case TCPS:
+		return "TCPS"
+	case TCSCAP:
+		return "TCSCAP"
+	case TCSwitch:
+		return "TCSwitch"
+	}
+	return fmt.Sprintf("equipment type %d", eqType)
+}
+
+// BranchesBetween returns all branches connecting the two buses, including parallel circuits of every branch
+// type. Branches are searched from both buses, three winding transformers are found by any two of their buses.
+// Branch handles are relative to the from bus where available.
+func (c *Client) BranchesBetween(fromHnd, toHnd int) ([]Branch, error) {
+	var branches []Branch
+	seen := make(map[int]bool)
+	for _, dir := range [2][2]int{{fromHnd, toHnd}, {toHnd, fromHnd}} {
+		for bi := c.NextBusEquipment(dir[0], TCBranch); bi.Next(); {
+			brHnd := bi.Hnd()
+
+			var bus2Hnd, eqHnd int
+			if err := c.GetData(brHnd, BRnBus2Hnd, BRnHandle).Scan(&bus2Hnd, &eqHnd); err != nil {
+				return nil, fmt.Errorf("BranchesBetween: %v", err)
+			}
+			eqType, err := c.EquipmentType(eqHnd)
+			if err != nil {
+				return nil, fmt.Errorf("BranchesBetween: %v", err)
+			}
+
+			// Three winding transformer branches may connect through the tertiary bus.
+			connected := bus2Hnd == dir[1]
+			if !connected && eqType == TCXFMR3 {
+				var bus3Hnd int
+				c.GetData(brHnd, BRnBus3Hnd).Scan(&bus3Hnd)
+				connected = bus3Hnd == dir[1]
+			}
+			if !connected || seen[eqHnd] {
+				continue
+			}
+			seen[eqHnd] = true
+
+			br := Branch{Hnd: brHnd, EqHnd: eqHnd, EqType: eqType, FromHnd: fromHnd, ToHnd: toHnd}
+			if tkn, ok := cktIDTokens[eqType]; ok {
+				if err := c.GetData(eqHnd, tkn).Scan(&br.CktID); err != nil {
+					return nil, fmt.Errorf("BranchesBetween: %v", err)
+				}
+				br.CktID = strings.TrimSpace(br.CktID)
+			}
+			branches = append(branches, br)
+		}
+	}
+	return branches, nil
+}
+
+// FindBranches returns all branches between the two buses, in either direction, see BranchesBetween.
+// Returns error if either bus cannot be found.
+func (c *Client) FindBranches(fName string, fKV float64, tName string, tKV float64) ([]Branch, error) {
+	fHnd, err := c.FindBusByName(fName, fKV)
+	if err != nil {
+		return nil, fmt.Errorf("FindBranches: %v", err)
+	}
+	tHnd, err := c.FindBusByName(tName, tKV)
+	if err != nil {
+		return nil, fmt.Errorf("FindBranches: %v", err)
+	}
+	return c.BranchesBetween(fHnd, tHnd)
+}
+
+// FindBranch searches for a branch with the given branch data, returns the branch handle.
 // Returns error if a branch cannot be found.
 func (c *Client) FindBranch(fName string, fKV float64, tName string, tKV float64, ckt string) (int, error) {
-	return c.findBranch(fName, fKV, tName, tKV, ckt)
+	br, err := c.findBranch(fName, fKV, tName, tKV, ckt, 0)
+	if err != nil {
+		return 0, err
+	}
+	return br.Hnd, nil
 }
 
 // FindLine searches for a line with the given branch data. From and To can be swapped and should return the same Line object.
 // Returns error if a line cannot be found, or if the branch specified points to a non-line object.
 func (c *Client) FindLine(fName string, fKV float64, tName string, tKV float64, ckt string) (*Line, error) {
-	brHnd, err := c.findBranch(fName, fKV, tName, tKV, ckt)
+	br, err := c.findBranch(fName, fKV, tName, tKV, ckt, TCLine)
 	if err != nil {
 		return nil, fmt.Errorf("FindLine: could not find line: %v", err)
 	}
+	return c.getLine(br.EqHnd)
+}
 
-	var lineHnd int
-	if c.GetData(brHnd, BRnHandle).Scan(&lineHnd); err != nil {
-		return nil, fmt.Errorf("FindLine: could not find line: %v", err)
+// FindTransformer searches for a two winding transformer with the given branch data. From and To can be swapped.
+// Returns error if a transformer cannot be found.
+func (c *Client) FindTransformer(fName string, fKV float64, tName string, tKV float64, ckt string) (*Xfmr, error) {
+	br, err := c.findBranch(fName, fKV, tName, tKV, ckt, TCXFMR)
+	if err != nil {
+		return nil, fmt.Errorf("FindTransformer: could not find transformer: %v", err)
 	}
+	return c.GetXfmr(br.EqHnd)
+}
 
-	if eqType, _ := c.EquipmentType(lineHnd); eqType != TCLine {
-		return nil, fmt.Errorf("FindLine: branch is not of type TCLine %v", err)
+// FindTransformer3 searches for a three winding transformer connected to any two of its buses.
+// Returns error if a transformer cannot be found.
+func (c *Client) FindTransformer3(aName string, aKV float64, bName string, bKV float64, ckt string) (*Xfmr3, error) {
+	br, err := c.findBranch(aName, aKV, bName, bKV, ckt, TCXFMR3)
+	if err != nil {
+		return nil, fmt.Errorf("FindTransformer3: could not find transformer: %v", err)
 	}
-
-	return c.getLine(lineHnd)
+	return c.GetXfmr3(br.EqHnd)
 }
 
-func (c *Client) findBranch(fName string, fKV float64, tName string, tKV float64, ckt string) (int, error) {
-	fHnd, err := c.FindBusByName(fName, fKV)
+// FindSwitch searches for a switch with the given branch data, returns the switch equipment handle.
+// Returns error if a switch cannot be found.
+func (c *Client) FindSwitch(fName string, fKV float64, tName string, tKV float64, ckt string) (int, error) {
+	br, err := c.findBranch(fName, fKV, tName, tKV, ckt, TCSwitch)
 	if err != nil {
-		return 0, err
+		return 0, fmt.Errorf("FindSwitch: could not find switch: %v", err)
 	}
+	return br.EqHnd, nil
+}
 
-	tHnd, err := c.FindBusByName(tName, tKV)
+// FindSeriesCap searches for a series capacitor or reactor with the given branch data, returns the series
+// capacitor equipment handle. Returns error if a series capacitor cannot be found.
+func (c *Client) FindSeriesCap(fName string, fKV float64, tName string, tKV float64, ckt string) (int, error) {
+	br, err := c.findBranch(fName, fKV, tName, tKV, ckt, TCSCAP)
 	if err != nil {
-		return 0, err
+		return 0, fmt.Errorf("FindSeriesCap: could not find series capacitor: %v", err)
 	}
+	return br.EqHnd, nil
+}
 
-	for bi := c.NextBusEquipment(fHnd, TCBranch); bi.Next(); {
-		brHnd := bi.Hnd()
-
-		var brBus2Hnd int
-		var brEqHnd int
-		if err := c.GetData(brHnd, BRnBus2Hnd, BRnHandle).Scan(&brBus2Hnd, &brEqHnd); err != nil {
-			return 0, err
-		}
-
-		// Get branch equipment type in order to obtain circuit ID.
-		brEqType, err := c.EquipmentType(brEqHnd)
-		if err != nil {
-			return 0, err
-		}
-
-		// Determin ckt id code dependent on equipment type.
-		var sID int
-		switch brEqType {
-		case TCLine:
-			sID = LNsID
-		case TCXFMR:
-			sID = XRsID
-		case TCXFMR3:
-			sID = X3sID
-		case TCPS:
-			sID = PSsID
-		case TCSC:
-			sID = SCsID
-		case TCSwitch:
-			sID = SWsID
-		default:
-			return 0, fmt.Errorf("findBranch: %s %0.2fkV-%s %0.2fkV ckt:%s unsupported equipment type %d", fName, fKV, tName, tKV, ckt, brEqHnd)
-		}
+// FindPhaseShifter searches for a phase shifting transformer with the given branch data, returns the phase
+// shifter equipment handle. Returns error if a phase shifter cannot be found.
+func (c *Client) FindPhaseShifter(fName string, fKV float64, tName string, tKV float64, ckt string) (int, error) {
+	br, err := c.findBranch(fName, fKV, tName, tKV, ckt, TCPS)
+	if err != nil {
+		return 0, fmt.Errorf("FindPhaseShifter: could not find phase shifter: %v", err)
+	}
+	return br.EqHnd, nil
+}
 
-		var cktID string
-		if err := c.GetData(brEqHnd, sID).Scan(&cktID); err != nil {
-			return 0, err
+// findBranch returns the branch between the buses with the circuit id. If eqType is non-zero, only branches of
+// the equipment type are considered.
+func (c *Client) findBranch(fName string, fKV float64, tName string, tKV float64, ckt string, eqType int) (Branch, error) {
+	branches, err := c.FindBranches(fName, fKV, tName, tKV)
+	if err != nil {
+		return Branch{}, err
+	}
+	for _, br := range branches {
+		if eqType != 0 && br.EqType != eqType {
+			continue
 		}
-
-		// Check to bus and ckt match, if match then branch found.
-		if brBus2Hnd == tHnd && strings.TrimSpace(cktID) == strings.TrimSpace(ckt) {
-			return brHnd, nil
+		if br.CktID == strings.TrimSpace(ckt) {
+			return br, nil
 		}
-
 	}
-	return 0, fmt.Errorf("findBranch: could not find %s %0.2fkV-%s %0.2fkV ckt:%s", fName, fKV, tName, tKV, ckt)
+	if eqType != 0 {
+		return Branch{}, fmt.Errorf("findBranch: could not find %s %0.2fkV-%s %0.2fkV ckt:%s of type %s", fName, fKV, tName, tKV, ckt, equipmentTypeName(eqType))
+	}
+	return Branch{}, fmt.Errorf("findBranch: could not find %s %0.2fkV-%s %0.2fkV ckt:%s", fName, fKV, tName, tKV, ckt)
 }
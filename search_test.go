@@ -48,3 +48,62 @@ func TestClient_FindLine(t *testing.T) {
 	}
 
 }
+
+func TestClient_FindBranches(t *testing.T) {
+	api := NewClient()
+	defer api.Release()
+
+	if err := api.LoadDataFile(testCase); err != nil {
+		t.Fatal(err)
+	}
+
+	// Branches are found in either direction.
+	fwd, err := api.FindBranches("FIELDALE", 132, "OHIO", 132)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rev, err := api.FindBranches("OHIO", 132, "FIELDALE", 132)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fwd) == 0 || len(fwd) != len(rev) {
+		t.Errorf("expected same branches in both directions, got %v and %v", fwd, rev)
+	}
+
+	if _, err := api.FindPhaseShifter("TENNESSEE", 132, "NEVADA", 132, "1"); err != nil {
+		t.Error(err)
+	}
+	if _, err := api.FindPhaseShifter("NEVADA", 132, "TENNESSEE", 132, "1"); err != nil {
+		t.Error(err)
+	}
+	if _, err := api.FindSwitch("TENNESSEE", 132, "NEVADA", 132, "1"); err == nil {
+		t.Error("expected error finding phase shifter as switch")
+	}
+
+	x3, err := api.FindTransformer3("NEVADA", 132, "NEW HAMPSHR", 33, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range []*Bus{x3.Bus1, x3.Bus2, x3.Bus3} {
+		if b == nil {
+			t.Fatalf("expected all three buses, got %v", x3)
+		}
+	}
+	// Any two of the three buses find the same transformer.
+	x3b, err := api.FindTransformer3(x3.Bus3.Name, x3.Bus3.KVNominal, x3.Bus2.Name, x3.Bus2.KVNominal, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x3b.String() != x3.String() {
+		t.Errorf("expected %v, got %v", x3, x3b)
+	}
+}
+
+func TestEquipmentTypeName(t *testing.T) {
+	if got := equipmentTypeName(TCSCAP); got != "TCSCAP" {
+		t.Errorf("expected TCSCAP, got %s", got)
+	}
+	if got := equipmentTypeName(TCDCLine2); got != "equipment type 39" {
+		t.Errorf("unexpected %s", got)
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"fmt"
+	"math/cmplx"
+	"sort"
+)
+
+// RelayNoOpTime is the operating time returned by GetRelayTime for relays which do not operate.
+const RelayNoOpTime = 9999.0
+
+// RelayResponse represents a relay response to a simulated fault.
+type RelayResponse struct {
+	Hnd    int
+	EqType int
+	ID     string
+	Time   float64    // Operating time in seconds, RelayNoOpTime if not operating.
+	Op     string     // Operation text.
+	Z      complex128 // Apparent impedance in primary ohms, distance relays only.
+}
+
+// Operated reports whether the relay operated.
+func (r RelayResponse) Operated() bool {
+	return r.Time < RelayNoOpTime
+}
+
+// SweepStep represents the results at a single fault resistance.
+type SweepStep struct {
+	R      float64 // Fault resistance in ohms.
+	Total  SeqPhase
+	Relays []RelayResponse
+}
+
+// Relay returns the response of the relay with the provided handle.
+func (s SweepStep) Relay(rlyHnd int) (RelayResponse, bool) {
+	for _, r := range s.Relays {
+		if r.Hnd == rlyHnd {
+			return r, true
+		}
+	}
+	return RelayResponse{}, false
+}
+
+// ResistanceSweep represents the results of a fault resistance sweep, ordered by increasing fault resistance.
+type ResistanceSweep struct {
+	Steps []SweepStep
+}
+
+// MaxDetected returns the maximum fault resistance the relay detects, the last resistance before the relay first
+// fails to operate. Returns false if the relay does not operate at the first step.
+func (s *ResistanceSweep) MaxDetected(rlyHnd int) (float64, bool) {
+	var r float64
+	var ok bool
+	for _, step := range s.Steps {
+		resp, found := step.Relay(rlyHnd)
+		if !found || !resp.Operated() {
+			break
+		}
+		r, ok = step.R, true
+	}
+	return r, ok
+}
+
+// Sensitivity returns the maximum detected fault resistance for each relay, see MaxDetected. Relays which do not
+// operate at the first step are omitted.
+func (s *ResistanceSweep) Sensitivity() map[int]float64 {
+	m := make(map[int]float64)
+	if len(s.Steps) == 0 {
+		return m
+	}
+	for _, resp := range s.Steps[0].Relays {
+		if r, ok := s.MaxDetected(resp.Hnd); ok {
+			m[resp.Hnd] = r
+		}
+	}
+	return m
+}
+
+// ResistanceSteps returns n+1 evenly spaced fault resistances from zero to max ohms.
+func ResistanceSteps(max float64, n int) []float64 {
+	if n < 1 {
+		return []float64{0}
+	}
+	rs := make([]float64, n+1)
+	for i := range rs {
+		rs[i] = max * float64(i) / float64(n)
+	}
+	return rs
+}
+
+// apparentImpedance returns the apparent impedance seen by a ground or phase distance element from the relay
+// phase voltages and currents, for the loop carrying the largest loop current. Ground loops are compensated by the
+// residual current factor k.
+func apparentImpedance(eqType int, v, i [3]Phasor, k complex128) complex128 {
+	var vLoop, iLoop complex128
+	for p := 0; p < 3; p++ {
+		var vp, ip complex128
+		if eqType == TCRLYDSG {
+			vp, ip = complex128(v[p]), complex128(i[p])+k*complex128(i[0]+i[1]+i[2])
+		} else {
+			q := (p + 1) % 3
+			vp, ip = complex128(v[p]-v[q]), complex128(i[p]-i[q])
+		}
+		if cmplx.Abs(ip) > cmplx.Abs(iLoop) {
+			vLoop, iLoop = vp, ip
+		}
+	}
+	if iLoop == 0 {
+		return cmplx.Inf()
+	}
+	return vLoop / iLoop
+}
+
+// sweepRelay represents a relay and its location for a resistance sweep.
+type sweepRelay struct {
+	RelayDevice
+	group *RelayGroup
+	k     complex128
+}
+
+// sweepRelays loads the relays within the relay groups.
+func (c *Client) sweepRelays(rlyGroupHnds []int) ([]sweepRelay, error) {
+	var relays []sweepRelay
+	for _, hnd := range rlyGroupHnds {
+		rg, err := c.GetRelayGroup(hnd)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range rg.Devices() {
+			r := sweepRelay{RelayDevice: d, group: rg}
+			if d.EqType == TCRLYDSG {
+				// Ignoring errors on optional data, defaults to uncompensated.
+				var kmag, kang float64
+				c.GetData(d.Hnd, DGdKmag, DGdKang).Scan(&kmag, &kang)
+				r.k = complex128(NewPhasor(kmag, kang))
+			}
+			relays = append(relays, r)
+		}
+	}
+	return relays, nil
+}
+
+// ResistanceSweep runs the fault defined by the equipment handle and config at each of the provided fault
+// resistances in ohms, recording the total fault current and the response of every relay within the provided
+// relay groups. The fault reactance of the config is retained. The config should define a single fault, only the
+// first fault of each run is considered. Previous fault results are cleared.
+func (c *Client) ResistanceSweep(hnd int, cfg *FaultConfig, resistances []float64, rlyGroupHnds ...int) (*ResistanceSweep, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("ResistanceSweep: config must not be nil")
+	}
+	relays, err := c.sweepRelays(rlyGroupHnds)
+	if err != nil {
+		return nil, fmt.Errorf("ResistanceSweep: %v", err)
+	}
+	rs := append([]float64(nil), resistances...)
+	sort.Float64s(rs)
+
+	sweep := &ResistanceSweep{Steps: make([]SweepStep, 0, len(rs))}
+	for _, r := range rs {
+		stepCfg := *cfg
+		FaultOptions(FaultRX(r, cfg.fltX), FaultClearPrev(true))(&stepCfg)
+		if err := c.DoFault(hnd, &stepCfg); err != nil {
+			return nil, fmt.Errorf("ResistanceSweep: R=%g: %v", r, err)
+		}
+		// Relay results require the relay groups to be within the calculated tiers.
+		if err := c.PickFault(SFFirst, 9); err != nil {
+			return nil, fmt.Errorf("ResistanceSweep: R=%g: %v", r, err)
+		}
+		step := SweepStep{R: r}
+		if step.Total, err = c.scCurrent(HNDSC); err != nil {
+			return nil, fmt.Errorf("ResistanceSweep: R=%g: %v", r, err)
+		}
+		for _, rly := range relays {
+			resp := RelayResponse{Hnd: rly.Hnd, EqType: rly.EqType, ID: rly.ID}
+			if resp.Time, resp.Op, err = c.GetRelayTime(rly.Hnd, 1, false); err != nil {
+				return nil, fmt.Errorf("ResistanceSweep: R=%g: relay %s: %v", r, rly.ID, err)
+			}
+			if rly.EqType == TCRLYDSG || rly.EqType == TCRLYDSP {
+				if resp.Z, err = c.relayApparentImpedance(rly); err != nil {
+					return nil, fmt.Errorf("ResistanceSweep: R=%g: relay %s: %v", r, rly.ID, err)
+				}
+			}
+			step.Relays = append(step.Relays, resp)
+		}
+		sweep.Steps = append(sweep.Steps, step)
+	}
+	return sweep, nil
+}
+
+// relayApparentImpedance returns the apparent impedance seen by the distance relay at its protected branch end.
+func (c *Client) relayApparentImpedance(rly sweepRelay) (complex128, error) {
+	var v [3]Phasor
+	var err error
+	if v[0], v[1], v[2], err = c.GetSCVoltagePhase(rly.group.BusHnd); err != nil {
+		return 0, err
+	}
+	res, err := c.GetSCCurrentTerminals(rly.group.EqHnd, SCPhaseRect)
+	if err != nil {
+		return 0, err
+	}
+	t, ok := res.Terminal(rly.group.BusHnd)
+	if !ok {
+		return 0, fmt.Errorf("no current at relay group bus %s", c.printID(rly.group.BusHnd))
+	}
+	// Voltages in kV, currents in amps.
+	return 1000 * apparentImpedance(rly.EqType, v, t.Values, rly.k), nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"math/cmplx"
+	"testing"
+)
+
+func TestResistanceSteps(t *testing.T) {
+	rs := ResistanceSteps(50, 5)
+	expected := []float64{0, 10, 20, 30, 40, 50}
+	if len(rs) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, rs)
+	}
+	for i := range rs {
+		if !almostEqual(rs[i], expected[i]) {
+			t.Errorf("expected %v, got %v", expected, rs)
+		}
+	}
+	if rs := ResistanceSteps(50, 0); len(rs) != 1 || rs[0] != 0 {
+		t.Errorf("expected single bolted step, got %v", rs)
+	}
+}
+
+func TestApparentImpedance(t *testing.T) {
+	zl := complex(2, 20)
+	k := complex(0.5, 0)
+
+	// Single line to ground fault on phase A, unfaulted phases carry no current.
+	ia := NewPhasor(1000, -80)
+	i := [3]Phasor{ia, 0, 0}
+	v := [3]Phasor{Phasor(zl * (1 + k) * complex128(ia)), NewPhasor(76, -120), NewPhasor(76, 120)}
+	if z := apparentImpedance(TCRLYDSG, v, i, k); cmplx.Abs(z-zl) > 1e-9 {
+		t.Errorf("expected ground loop %v, got %v", zl, z)
+	}
+
+	// Phase to phase fault between B and C.
+	ib := NewPhasor(1000, -170)
+	i = [3]Phasor{0, ib, -ib}
+	v = [3]Phasor{NewPhasor(76, 0), Phasor(zl * complex128(ib)), Phasor(-zl * complex128(ib))}
+	if z := apparentImpedance(TCRLYDSP, v, i, 0); cmplx.Abs(z-zl) > 1e-9 {
+		t.Errorf("expected phase loop %v, got %v", zl, z)
+	}
+
+	if z := apparentImpedance(TCRLYDSP, v, [3]Phasor{}, 0); !cmplx.IsInf(z) {
+		t.Errorf("expected infinite impedance without current, got %v", z)
+	}
+}
+
+func TestResistanceSweep_MaxDetected(t *testing.T) {
+	step := func(r float64, times ...float64) SweepStep {
+		s := SweepStep{R: r}
+		for i, tm := range times {
+			s.Relays = append(s.Relays, RelayResponse{Hnd: i + 1, Time: tm})
+		}
+		return s
+	}
+	sweep := &ResistanceSweep{Steps: []SweepStep{
+		step(0, 0.1, 0.5, RelayNoOpTime),
+		step(10, 0.2, RelayNoOpTime, RelayNoOpTime),
+		step(20, 0.4, 0.9, RelayNoOpTime),
+		step(30, RelayNoOpTime, RelayNoOpTime, RelayNoOpTime),
+	}}
+	tests := []struct {
+		hnd int
+		r   float64
+		ok  bool
+	}{
+		{1, 20, true},
+		{2, 0, true},
+		{3, 0, false},
+	}
+	for _, tt := range tests {
+		r, ok := sweep.MaxDetected(tt.hnd)
+		if r != tt.r || ok != tt.ok {
+			t.Errorf("relay %d: expected %v %v, got %v %v", tt.hnd, tt.r, tt.ok, r, ok)
+		}
+	}
+	if m := sweep.Sensitivity(); len(m) != 2 || m[1] != 20 {
+		t.Errorf("unexpected sensitivity %v", m)
+	}
+}
+
+func TestClient_ResistanceSweep(t *testing.T) {
+	c := NewClient()
+	defer c.Release()
+	if err := c.LoadDataFile(testCase); err != nil {
+		t.Fatal(err)
+	}
+	var rgHnds []int
+	for rg := c.NextEquipment(TCRLYGroup); rg.Next(); {
+		rgHnds = append(rgHnds, rg.Hnd())
+	}
+	if len(rgHnds) == 0 {
+		t.Fatal("expected relay groups in test case")
+	}
+	sweep, err := c.ResistanceSweep(rgHnds[0], NewFaultConfig(FaultConn(AG), FaultCloseIn()), ResistanceSteps(100, 4), rgHnds[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sweep.Steps) != 5 {
+		t.Fatalf("expected 5 steps, got %d", len(sweep.Steps))
+	}
+	first, last := sweep.Steps[0].Total.Seq[0].Mag(), sweep.Steps[4].Total.Seq[0].Mag()
+	if last >= first {
+		t.Errorf("expected fault current to decrease with resistance, got %v to %v", first, last)
+	}
+}
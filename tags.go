@@ -0,0 +1,337 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TagQuery represents a parsed boolean tag expression. Obtained from ParseTagQuery.
+//
+// Expressions are made up of tag names combined with the operators & (and), | (or), ! (not)
+// and grouped using parentheses. Operator precedence from highest to lowest is !, &, |.
+// Tag names containing spaces or operator characters may be double quoted, e.g.
+//
+//	(PROT_REVIEW & 138KV) | !RETIRED
+//	"Hello World" & !(A | B)
+type TagQuery struct {
+	expr tagExpr
+	src  string
+}
+
+// ParseTagQuery parses the provided tag expression, returns an error if the expression is malformed.
+func ParseTagQuery(s string) (TagQuery, error) {
+	p := tagParser{src: s}
+	if err := p.lex(); err != nil {
+		return TagQuery{}, fmt.Errorf("ParseTagQuery: %v", err)
+	}
+	if len(p.toks) == 0 {
+		return TagQuery{}, fmt.Errorf("ParseTagQuery: empty expression")
+	}
+	expr, err := p.parseOr()
+	if err != nil {
+		return TagQuery{}, fmt.Errorf("ParseTagQuery: %v", err)
+	}
+	if p.pos < len(p.toks) {
+		t := p.toks[p.pos]
+		return TagQuery{}, fmt.Errorf("ParseTagQuery: unexpected %q at position %d", t.val, t.off)
+	}
+	return TagQuery{expr: expr, src: s}, nil
+}
+
+// MustParseTagQuery is like ParseTagQuery but panics if the expression cannot be parsed.
+func MustParseTagQuery(s string) TagQuery {
+	q, err := ParseTagQuery(s)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+// Match reports whether the provided tags satisfy the query. The zero value TagQuery matches nothing.
+func (q TagQuery) Match(tags []string) bool {
+	if q.expr == nil {
+		return false
+	}
+	return q.expr.eval(tags)
+}
+
+// String returns the normalized, fully parenthesized query expression.
+func (q TagQuery) String() string {
+	if q.expr == nil {
+		return ""
+	}
+	return q.expr.String()
+}
+
+// tagExpr represents a node in a parsed tag query.
+type tagExpr interface {
+	eval(tags []string) bool
+	String() string
+}
+
+type tagIdent string
+
+func (t tagIdent) eval(tags []string) bool {
+	for _, tag := range tags {
+		if tag == string(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t tagIdent) String() string {
+	if strings.ContainsAny(string(t), " \t&|!()\"") {
+		return fmt.Sprintf("%q", string(t))
+	}
+	return string(t)
+}
+
+type tagNot struct{ x tagExpr }
+
+func (n tagNot) eval(tags []string) bool { return !n.x.eval(tags) }
+func (n tagNot) String() string          { return "!" + n.x.String() }
+
+type tagAnd struct{ x, y tagExpr }
+
+func (a tagAnd) eval(tags []string) bool { return a.x.eval(tags) && a.y.eval(tags) }
+func (a tagAnd) String() string          { return "(" + a.x.String() + " & " + a.y.String() + ")" }
+
+type tagOr struct{ x, y tagExpr }
+
+func (o tagOr) eval(tags []string) bool { return o.x.eval(tags) || o.y.eval(tags) }
+func (o tagOr) String() string          { return "(" + o.x.String() + " | " + o.y.String() + ")" }
+
+// tagToken represents a lexical token of a tag query, val is either an operator or tag name.
+type tagToken struct {
+	val   string
+	ident bool
+	off   int
+}
+
+// tagParser is a recursive descent parser for tag queries.
+type tagParser struct {
+	src  string
+	toks []tagToken
+	pos  int
+}
+
+// lex splits the source expression into tokens.
+func (p *tagParser) lex() error {
+	s := p.src
+	for i := 0; i < len(s); {
+		switch ch := s[i]; {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			i++
+		case strings.IndexByte("&|!()", ch) >= 0:
+			p.toks = append(p.toks, tagToken{val: string(ch), off: i})
+			i++
+		case ch == '"':
+			j := strings.IndexByte(s[i+1:], '"')
+			if j < 0 {
+				return fmt.Errorf("unterminated quoted tag at position %d", i)
+			}
+			p.toks = append(p.toks, tagToken{val: s[i+1 : i+1+j], ident: true, off: i})
+			i += j + 2
+		default:
+			j := i
+			for j < len(s) && strings.IndexByte(" \t\n\r&|!()\"", s[j]) < 0 {
+				j++
+			}
+			p.toks = append(p.toks, tagToken{val: s[i:j], ident: true, off: i})
+			i = j
+		}
+	}
+	return nil
+}
+
+// peek reports whether the next token is the provided operator.
+func (p *tagParser) peek(op string) bool {
+	return p.pos < len(p.toks) && !p.toks[p.pos].ident && p.toks[p.pos].val == op
+}
+
+func (p *tagParser) parseOr() (tagExpr, error) {
+	x, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek("|") {
+		p.pos++
+		y, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		x = tagOr{x, y}
+	}
+	return x, nil
+}
+
+func (p *tagParser) parseAnd() (tagExpr, error) {
+	x, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek("&") {
+		p.pos++
+		y, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		x = tagAnd{x, y}
+	}
+	return x, nil
+}
+
+func (p *tagParser) parseUnary() (tagExpr, error) {
+	if p.pos >= len(p.toks) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	t := p.toks[p.pos]
+	p.pos++
+	switch {
+	case t.ident:
+		return tagIdent(t.val), nil
+	case t.val == "!":
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return tagNot{x}, nil
+	case t.val == "(":
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.peek(")") {
+			return nil, fmt.Errorf("missing closing parenthesis for position %d", t.off)
+		}
+		p.pos++
+		return x, nil
+	}
+	return nil, fmt.Errorf("unexpected %q at position %d", t.val, t.off)
+}
+
+// TagsMatch reports whether the tags of the equipment with the provided handle satisfy the query.
+func (c *Client) TagsMatch(hnd int, q TagQuery) (bool, error) {
+	tags, err := c.TagsGet(hnd)
+	if err != nil {
+		return false, err
+	}
+	return q.Match(tags), nil
+}
+
+// NextEquipmentByTagQuery returns a HandleIterator which loops through all equipment of the provided type
+// whose tags satisfy the query. Tags are evaluated using TagsGet, equipment which tags cannot be retrieved
+// for are skipped. See NextEquipment for more details.
+func (c *Client) NextEquipmentByTagQuery(eqType int, q TagQuery) HandleIterator {
+	return &handleIterator{
+		f: func(hnd *int) error {
+			for {
+				if err := c.olxAPI.GetEquipment(eqType, hnd); err != nil {
+					return err
+				}
+				if ok, _ := c.TagsMatch(*hnd, q); ok {
+					return nil
+				}
+			}
+		},
+	}
+}
+
+// TagSet represents the deduplicated set of tags of an equipment object. Obtain using LoadTagSet,
+// modify with Add and Remove, and write back with Save. Tag order is preserved.
+type TagSet struct {
+	c       *Client
+	hnd     int
+	tags    []string
+	changed bool
+}
+
+// LoadTagSet returns the TagSet for the equipment with the provided handle. Duplicate and empty
+// tags stored on the equipment are removed, which marks the set as changed.
+func (c *Client) LoadTagSet(hnd int) (*TagSet, error) {
+	tags, err := c.TagsGet(hnd)
+	if err != nil {
+		return nil, err
+	}
+	s := &TagSet{c: c, hnd: hnd}
+	s.Add(tags...)
+	s.changed = len(s.tags) != len(tags)
+	return s, nil
+}
+
+// Hnd returns the equipment handle the TagSet was loaded from.
+func (s *TagSet) Hnd() int {
+	return s.hnd
+}
+
+// Has reports whether tag is within the set.
+func (s *TagSet) Has(tag string) bool {
+	return tagIdent(tag).eval(s.tags)
+}
+
+// Add adds the provided tags to the set, skipping empty and existing tags. Reports whether the set changed.
+func (s *TagSet) Add(tags ...string) bool {
+	var changed bool
+	for _, tag := range tags {
+		if tag == "" || s.Has(tag) {
+			continue
+		}
+		s.tags = append(s.tags, tag)
+		changed = true
+	}
+	s.changed = s.changed || changed
+	return changed
+}
+
+// Remove removes the provided tags from the set. Reports whether the set changed.
+func (s *TagSet) Remove(tags ...string) bool {
+	var changed bool
+	for _, tag := range tags {
+		for i, t := range s.tags {
+			if t == tag {
+				s.tags = append(s.tags[:i], s.tags[i+1:]...)
+				changed = true
+				break
+			}
+		}
+	}
+	s.changed = s.changed || changed
+	return changed
+}
+
+// Tags returns a copy of the tags within the set.
+func (s *TagSet) Tags() []string {
+	tags := make([]string, len(s.tags))
+	copy(tags, s.tags)
+	return tags
+}
+
+// Match reports whether the tags within the set satisfy the query.
+func (s *TagSet) Match(q TagQuery) bool {
+	return q.Match(s.tags)
+}
+
+// Changed reports whether the set has been modified since it was loaded or last saved.
+func (s *TagSet) Changed() bool {
+	return s.changed
+}
+
+// Save writes the tags back to the equipment using TagsSet, only if the set has changed.
+func (s *TagSet) Save() error {
+	if !s.changed {
+		return nil
+	}
+	if s.c == nil {
+		return fmt.Errorf("Save: TagSet not loaded from client")
+	}
+	if err := s.c.TagsSet(s.hnd, s.tags...); err != nil {
+		return err
+	}
+	s.changed = false
+	return nil
+}
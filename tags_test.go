@@ -0,0 +1,138 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"testing"
+)
+
+func TestParseTagQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		tags     []string
+		expected bool
+		str      string
+	}{
+		{name: "ident", query: "A", tags: []string{"A"}, expected: true, str: "A"},
+		{name: "ident missing", query: "A", tags: []string{"B"}, expected: false, str: "A"},
+		{name: "not", query: "!RETIRED", tags: nil, expected: true, str: "!RETIRED"},
+		{name: "and", query: "A & B", tags: []string{"B", "A"}, expected: true, str: "(A & B)"},
+		{name: "and partial", query: "A & B", tags: []string{"A"}, expected: false, str: "(A & B)"},
+		{name: "or", query: "A | B", tags: []string{"B"}, expected: true, str: "(A | B)"},
+		{name: "precedence", query: "A | B & C", tags: []string{"A"}, expected: true, str: "(A | (B & C))"},
+		{name: "precedence not", query: "!A & B", tags: []string{"B"}, expected: true, str: "(!A & B)"},
+		{name: "group", query: "(PROT_REVIEW & 138KV) | !RETIRED", tags: []string{"RETIRED", "138KV"}, expected: false, str: "((PROT_REVIEW & 138KV) | !RETIRED)"},
+		{name: "group match", query: "(PROT_REVIEW & 138KV) | !RETIRED", tags: []string{"RETIRED", "138KV", "PROT_REVIEW"}, expected: true, str: "((PROT_REVIEW & 138KV) | !RETIRED)"},
+		{name: "quoted", query: `"Hello World" & !(A | B)`, tags: []string{"Hello World"}, expected: true, str: `("Hello World" & !(A | B))`},
+		{name: "double not", query: "!!A", tags: []string{"A"}, expected: true, str: "!!A"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := ParseTagQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := q.Match(tt.tags); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+			if got := q.String(); got != tt.str {
+				t.Errorf("expected %q, got %q", tt.str, got)
+			}
+		})
+	}
+}
+
+func TestParseTagQuery_Errors(t *testing.T) {
+	tests := []string{
+		"",
+		"   ",
+		"A &",
+		"A B",
+		"(A | B",
+		"A | B)",
+		"& A",
+		`"unterminated`,
+		"!",
+	}
+	for _, tt := range tests {
+		t.Run(tt, func(t *testing.T) {
+			if _, err := ParseTagQuery(tt); err == nil {
+				t.Errorf("expected error for %q, got nil", tt)
+			}
+		})
+	}
+}
+
+func TestTagSet(t *testing.T) {
+	s := &TagSet{}
+	if !s.Add("A", "B", "A", "") {
+		t.Errorf("expected add to report changed")
+	}
+	if s.Add("B") {
+		t.Errorf("expected duplicate add to report unchanged")
+	}
+	if got := s.Tags(); len(got) != 2 || got[0] != "A" || got[1] != "B" {
+		t.Errorf("expected [A B], got %v", got)
+	}
+	if !s.Has("A") || s.Has("C") {
+		t.Errorf("unexpected Has results for %v", s.Tags())
+	}
+	if s.Remove("C") {
+		t.Errorf("expected remove of missing tag to report unchanged")
+	}
+	if !s.Remove("A") {
+		t.Errorf("expected remove to report changed")
+	}
+	if !s.Match(MustParseTagQuery("B & !A")) {
+		t.Errorf("expected match for %v", s.Tags())
+	}
+	if !s.Changed() {
+		t.Errorf("expected set to be changed")
+	}
+}
+
+func TestClient_LoadTagSet(t *testing.T) {
+	c := NewClient()
+	defer c.Release()
+	if err := c.LoadDataFile(testCase); err != nil {
+		t.Fatal(err)
+	}
+	busHnd, err := c.FindBusByName("TENNESSEE", 132)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.TagsSet(busHnd, "A", "B", "A"); err != nil {
+		t.Fatal(err)
+	}
+	s, err := c.LoadTagSet(busHnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Changed() {
+		t.Errorf("expected duplicate tags to mark set changed")
+	}
+	s.Add("138KV")
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+	tags, err := c.TagsGet(busHnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 3 {
+		t.Errorf("expected 3 tags, got %v", tags)
+	}
+
+	var found bool
+	for ei := c.NextEquipmentByTagQuery(TCBus, MustParseTagQuery("138KV & !RETIRED")); ei.Next(); {
+		if ei.Hnd() == busHnd {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected bus %d to match tag query", busHnd)
+	}
+}
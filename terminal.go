@@ -0,0 +1,163 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"fmt"
+)
+
+// SCStyle represents the short circuit result output style passed to GetSCCurrent and GetSCVoltage.
+type SCStyle int
+
+// Short circuit result output styles.
+const (
+	SCSeqRect    SCStyle = iota + 1 // 012 sequence quantities in rectangular form.
+	SCSeqPolar                      // 012 sequence quantities in polar form.
+	SCPhaseRect                     // ABC phase quantities in rectangular form.
+	SCPhasePolar                    // ABC phase quantities in polar form.
+)
+
+// Seq reports whether the style returns sequence quantities.
+func (s SCStyle) Seq() bool {
+	return s == SCSeqRect || s == SCSeqPolar
+}
+
+// polar reports whether the style returns quantities in polar form.
+func (s SCStyle) polar() bool {
+	return s == SCSeqPolar || s == SCPhasePolar
+}
+
+// terminalBusTokens maps equipment types to the bus handle tokens of each terminal, in the order results are
+// returned by GetSCCurrent and GetSCVoltage.
+var terminalBusTokens = map[int][]int{
+	TCLoad:   {LDnBusHnd},
+	TCGen:    {GEnBusHnd},
+	TCShunt:  {SHnBusHnd},
+	TCSVD:    {SVnBusHnd},
+	TCLine:   {LNnBus1Hnd, LNnBus2Hnd},
+	TCXFMR:   {XRnBus1Hnd, XRnBus2Hnd},
+	TCXFMR3:  {X3nBus1Hnd, X3nBus2Hnd, X3nBus3Hnd},
+	TCPS:     {PSnBus1Hnd, PSnBus2Hnd},
+	TCSCAP:   {SCnBus1Hnd, SCnBus2Hnd},
+	TCSwitch: {SWnBus1Hnd, SWnBus2Hnd},
+}
+
+// SCTerminal represents a short circuit quantity at one terminal of a bus or piece of equipment.
+type SCTerminal struct {
+	BusHnd int       // Terminal bus handle, zero if not applicable, e.g. total fault current.
+	Values [3]Phasor // Sequence 0, 1, 2 or phase A, B, C quantities depending on the style.
+}
+
+// SCResult represents a short circuit current or voltage result for every terminal of a bus or piece of
+// equipment. Terminals are ordered from end, to end, then tertiary for three winding transformers.
+type SCResult struct {
+	Hnd       int
+	Style     SCStyle
+	Terminals []SCTerminal
+}
+
+// Terminal returns the result at the terminal connected to the bus.
+func (r *SCResult) Terminal(busHnd int) (SCTerminal, bool) {
+	for _, t := range r.Terminals {
+		if t.BusHnd == busHnd {
+			return t, true
+		}
+	}
+	return SCTerminal{}, false
+}
+
+// terminalBuses returns the equipment handle results are read from and its terminal bus handles, for the provided
+// handle. Branch handles are resolved to their underlying equipment.
+func (c *Client) terminalBuses(hnd int) (int, []int, error) {
+	return resolveTerminals(hnd, c.EquipmentType, func(hnd, tkn int) (int, error) {
+		var v int
+		err := c.GetData(hnd, tkn).Scan(&v)
+		return v, err
+	})
+}
+
+// resolveTerminals returns the equipment handle and terminal bus handles for the provided handle, using the
+// provided equipment type and integer data lookups.
+func resolveTerminals(hnd int, eqType func(hnd int) (int, error), data func(hnd, tkn int) (int, error)) (int, []int, error) {
+	if hnd == HNDSC {
+		return hnd, []int{0}, nil
+	}
+	typ, err := eqType(hnd)
+	if err != nil {
+		return 0, nil, err
+	}
+	if typ == TCBranch {
+		if hnd, err = data(hnd, BRnHandle); err != nil {
+			return 0, nil, err
+		}
+		if typ, err = eqType(hnd); err != nil {
+			return 0, nil, err
+		}
+	}
+	if typ == TCBus {
+		return hnd, []int{hnd}, nil
+	}
+	tkns, ok := terminalBusTokens[typ]
+	if !ok {
+		return hnd, []int{0}, nil
+	}
+	buses := make([]int, len(tkns))
+	for i, tkn := range tkns {
+		if buses[i], err = data(hnd, tkn); err != nil {
+			return 0, nil, err
+		}
+	}
+	return hnd, buses, nil
+}
+
+// newSCResult builds a short circuit result from the raw output arrays.
+func newSCResult(hnd int, style SCStyle, buses []int, vdOut1, vdOut2 []float64) *SCResult {
+	r := &SCResult{Hnd: hnd, Style: style}
+	for i, busHnd := range buses {
+		if 3*i+3 > len(vdOut1) {
+			break
+		}
+		t := SCTerminal{BusHnd: busHnd}
+		for j := 0; j < 3; j++ {
+			if style.polar() {
+				t.Values[j] = NewPhasor(vdOut1[3*i+j], vdOut2[3*i+j])
+			} else {
+				t.Values[j] = Phasor(complex(vdOut1[3*i+j], vdOut2[3*i+j]))
+			}
+		}
+		r.Terminals = append(r.Terminals, t)
+	}
+	return r
+}
+
+// GetSCCurrentTerminals gets the short circuit current at every terminal of the equipment with the provided
+// handle, in the provided style. Use HNDSC for the total fault current. Branch handles are resolved to their
+// underlying equipment. PickFault must be called first.
+func (c *Client) GetSCCurrentTerminals(hnd int, style SCStyle) (*SCResult, error) {
+	eqHnd, buses, err := c.terminalBuses(hnd)
+	if err != nil {
+		return nil, fmt.Errorf("GetSCCurrentTerminals: %v", err)
+	}
+	vdOut1, vdOut2, err := c.olxAPI.GetSCCurrent(eqHnd, int(style))
+	if err != nil {
+		return nil, fmt.Errorf("GetSCCurrentTerminals: %v", err)
+	}
+	return newSCResult(hnd, style, buses, vdOut1[:], vdOut2[:]), nil
+}
+
+// GetSCVoltageTerminals gets the short circuit voltage at every terminal bus of the bus or equipment with the
+// provided handle, in the provided style. Branch handles are resolved to their underlying equipment. PickFault must
+// be called first.
+func (c *Client) GetSCVoltageTerminals(hnd int, style SCStyle) (*SCResult, error) {
+	eqHnd, buses, err := c.terminalBuses(hnd)
+	if err != nil {
+		return nil, fmt.Errorf("GetSCVoltageTerminals: %v", err)
+	}
+	vdOut1, vdOut2, err := c.olxAPI.GetSCVoltage(eqHnd, int(style))
+	if err != nil {
+		return nil, fmt.Errorf("GetSCVoltageTerminals: %v", err)
+	}
+	return newSCResult(hnd, style, buses, vdOut1[:], vdOut2[:]), nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestNewSCResult(t *testing.T) {
+	var rect1, rect2, polar1, polar2 [12]float64
+	for i := 0; i < 9; i++ {
+		p := NewPhasor(float64(i+1), float64(-30*i))
+		rect1[i], rect2[i] = real(p), imag(p)
+		polar1[i], polar2[i] = p.Mag(), p.Ang()
+	}
+	buses := []int{10, 20, 30}
+	rect := newSCResult(1, SCPhaseRect, buses, rect1[:], rect2[:])
+	polar := newSCResult(1, SCPhasePolar, buses, polar1[:], polar2[:])
+	if len(rect.Terminals) != 3 || len(polar.Terminals) != 3 {
+		t.Fatalf("expected 3 terminals, got %d and %d", len(rect.Terminals), len(polar.Terminals))
+	}
+	for i := range rect.Terminals {
+		for j := 0; j < 3; j++ {
+			if d := (rect.Terminals[i].Values[j] - polar.Terminals[i].Values[j]).Mag(); d > 1e-9 {
+				t.Errorf("terminal %d value %d: rectangular and polar differ by %v", i, j, d)
+			}
+		}
+	}
+	to, ok := polar.Terminal(20)
+	if !ok || !almostEqual(to.Values[0].Mag(), 4) {
+		t.Errorf("expected to end terminal, got %v", to)
+	}
+	if _, ok := polar.Terminal(40); ok {
+		t.Error("unexpected terminal for bus 40")
+	}
+
+	// Voltage arrays hold at most three terminals.
+	if r := newSCResult(1, SCSeqRect, []int{1, 2, 3, 4}, rect1[:9], rect2[:9]); len(r.Terminals) != 3 {
+		t.Errorf("expected 3 terminals, got %d", len(r.Terminals))
+	}
+}
+
+func TestResolveTerminals(t *testing.T) {
+	// Branch 100 at bus 1 of line 200 between buses 1 and 2.
+	types := map[int]int{1: TCBus, 100: TCBranch, 200: TCLine, 300: TCGen}
+	data := map[[2]int]int{
+		{100, BRnHandle}:  200,
+		{200, LNnBus1Hnd}: 1,
+		{200, LNnBus2Hnd}: 2,
+		{300, GEnBusHnd}:  1,
+	}
+	eqType := func(hnd int) (int, error) {
+		if typ, ok := types[hnd]; ok {
+			return typ, nil
+		}
+		return 0, fmt.Errorf("invalid handle %d", hnd)
+	}
+	lookup := func(hnd, tkn int) (int, error) {
+		if v, ok := data[[2]int{hnd, tkn}]; ok {
+			return v, nil
+		}
+		return 0, fmt.Errorf("invalid token %d for handle %d", tkn, hnd)
+	}
+	tests := []struct {
+		hnd, eqHnd int
+		buses      []int
+	}{
+		{HNDSC, HNDSC, []int{0}},
+		{1, 1, []int{1}},
+		{100, 200, []int{1, 2}},
+		{200, 200, []int{1, 2}},
+		{300, 300, []int{1}},
+	}
+	for _, tt := range tests {
+		eqHnd, buses, err := resolveTerminals(tt.hnd, eqType, lookup)
+		if err != nil {
+			t.Errorf("%d: unexpected error: %v", tt.hnd, err)
+			continue
+		}
+		if eqHnd != tt.eqHnd || !reflect.DeepEqual(buses, tt.buses) {
+			t.Errorf("%d: expected %d %v, got %d %v", tt.hnd, tt.eqHnd, tt.buses, eqHnd, buses)
+		}
+	}
+	if _, _, err := resolveTerminals(400, eqType, lookup); err == nil {
+		t.Error("expected error for invalid handle")
+	}
+}
+
+func TestSCStyle(t *testing.T) {
+	if !SCSeqRect.Seq() || !SCSeqPolar.Seq() || SCPhaseRect.Seq() || SCPhasePolar.Seq() {
+		t.Error("unexpected sequence styles")
+	}
+	if SCSeqRect.polar() || !SCSeqPolar.polar() || SCPhaseRect.polar() || !SCPhasePolar.polar() {
+		t.Error("unexpected polar styles")
+	}
+}
+
+func TestClient_GetSCCurrentTerminals(t *testing.T) {
+	c := NewClient()
+	defer c.Release()
+	if err := c.LoadDataFile(testCase); err != nil {
+		t.Fatal(err)
+	}
+	line, err := c.FindLine("FIELDALE", 132, "OHIO", 132, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DoFault(line.Bus1.Hnd, NewFaultConfig(FaultCloseIn(), FaultConn(AG))); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.PickFault(SFFirst, 1); err != nil {
+		t.Fatal(err)
+	}
+	i, err := c.GetSCCurrentTerminals(line.Hnd, SCPhasePolar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(i.Terminals) != 2 || i.Terminals[0].BusHnd != line.Bus1.Hnd || i.Terminals[1].BusHnd != line.Bus2.Hnd {
+		t.Errorf("expected line terminals, got %v", i.Terminals)
+	}
+	v, err := c.GetSCVoltageTerminals(line.Hnd, SCSeqRect)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(v.Terminals) != 2 {
+		t.Errorf("expected 2 terminals, got %v", v.Terminals)
+	}
+}
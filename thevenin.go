@@ -0,0 +1,225 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"math/cmplx"
+	"strconv"
+	"strings"
+)
+
+// Thevenin represents the Thevenin equivalent sequence impedances at a bus, derived from bolted 3LG and 1LG
+// close-in faults. Impedances and voltages are per unit on the bus base.
+type Thevenin struct {
+	BusHnd int
+	Name   string
+	KV     float64
+	Base   Base
+
+	Vf         Phasor     // Pre-fault phase A voltage.
+	Z1, Z2, Z0 complex128 // Sequence impedances.
+
+	// No zero sequence path to ground at the bus, e.g. an ungrounded system. Z0 is infinite and the 1LG fault
+	// current is zero.
+	NoGroundPath bool
+
+	I3LG float64 // Bolted 3LG fault current in amps.
+	I1LG float64 // Bolted 1LG fault current in amps.
+
+	// X/R ratios reported by Oneliner, zero where not available.
+	XR3LG, XR1LG         float64
+	XRANSI3LG, XRANSI1LG float64
+}
+
+func (th *Thevenin) String() string {
+	return fmt.Sprintf("%s %0.2fkV Z1:%0.5f Z0:%0.5f", th.Name, th.KV, th.Z1, th.Z0)
+}
+
+// Z1Ohms returns the positive sequence impedance in ohms.
+func (th *Thevenin) Z1Ohms() complex128 {
+	return th.Base.ImpedanceFromPU(th.Z1)
+}
+
+// Z2Ohms returns the negative sequence impedance in ohms.
+func (th *Thevenin) Z2Ohms() complex128 {
+	return th.Base.ImpedanceFromPU(th.Z2)
+}
+
+// Z0Ohms returns the zero sequence impedance in ohms.
+func (th *Thevenin) Z0Ohms() complex128 {
+	return th.Base.ImpedanceFromPU(th.Z0)
+}
+
+// MVA3LG returns the three phase short circuit MVA.
+func (th *Thevenin) MVA3LG() float64 {
+	return sqrt3 * th.Base.KV * th.I3LG / 1000
+}
+
+// MVA1LG returns the single line to ground short circuit MVA, as sqrt(3)*kV*I.
+func (th *Thevenin) MVA1LG() float64 {
+	return sqrt3 * th.Base.KV * th.I1LG / 1000
+}
+
+// XR1 returns the positive sequence X/R ratio of the equivalent impedance.
+func (th *Thevenin) XR1() float64 {
+	return xr(th.Z1)
+}
+
+// XR0 returns the zero sequence X/R ratio of the equivalent impedance, zero where there is no ground path.
+func (th *Thevenin) XR0() float64 {
+	if th.NoGroundPath {
+		return 0
+	}
+	return xr(th.Z0)
+}
+
+// xr returns the X/R ratio of the impedance, infinite for a purely reactive impedance.
+func xr(z complex128) float64 {
+	if real(z) == 0 {
+		return math.Inf(1)
+	}
+	return imag(z) / real(z)
+}
+
+// theveninFault runs a bolted close-in fault at the bus and returns the fault sequence currents in per unit,
+// along with the reported X/R ratios.
+func (c *Client) theveninFault(busHnd int, conn FltConn, base Base) (i0, i1, i2 Phasor, xr, xrANSI float64, err error) {
+	if err = c.DoFault(busHnd, NewFaultConfig(FaultCloseIn(), FaultConn(conn), FaultClearPrev(true))); err != nil {
+		return
+	}
+	if err = c.PickFault(SFFirst, 1); err != nil {
+		return
+	}
+	if i0, i1, i2, err = c.GetSCCurrentSeq(HNDSC); err != nil {
+		return
+	}
+	i0, i1, i2 = base.CurrentPhasorToPU(i0), base.CurrentPhasorToPU(i1), base.CurrentPhasorToPU(i2)
+
+	// Ignoring errors on optional data, X/R ratios are not reported by all Oneliner versions.
+	c.GetData(HNDSC, FTdXR).Scan(&xr)
+	c.GetData(HNDSC, FTdXRANSI).Scan(&xrANSI)
+	return
+}
+
+// Thevenin returns the Thevenin equivalent at the bus. Bolted 3LG and 1LG close-in faults are simulated,
+// clearing previous fault results. Z1 is derived from the 3LG positive sequence current, Z2 from the 1LG
+// negative sequence bus voltage and current, and Z0 from the remaining 1LG series impedance.
+func (c *Client) Thevenin(busHnd int) (*Thevenin, error) {
+	if eqType, _ := c.EquipmentType(busHnd); eqType != TCBus {
+		return nil, fmt.Errorf("Thevenin: equipment type must be TCBus")
+	}
+	th := Thevenin{BusHnd: busHnd}
+	if err := c.GetData(busHnd, BUSsName, BUSdKVnominal).Scan(&th.Name, &th.KV); err != nil {
+		return nil, fmt.Errorf("Thevenin: could not scan bus data %v", err)
+	}
+	th.Name = strings.TrimSpace(th.Name)
+	var baseMVA float64
+	if err := c.GetData(HNDSYS, SYdBaseMVA).Scan(&baseMVA); err != nil {
+		return nil, fmt.Errorf("Thevenin: could not scan system data %v", err)
+	}
+	th.Base = Base{MVA: baseMVA, KV: th.KV}
+
+	// Bolted three phase fault.
+	_, i1, _, xr3, xrANSI3, err := c.theveninFault(busHnd, ABC, th.Base)
+	if err != nil {
+		return nil, fmt.Errorf("Thevenin: 3LG fault: %v", err)
+	}
+	vf, err := c.GetPSCVoltagePU(busHnd)
+	if err != nil {
+		return nil, fmt.Errorf("Thevenin: %v", err)
+	}
+	if i1 == 0 {
+		return nil, fmt.Errorf("Thevenin: zero 3LG fault current at %s", th.Name)
+	}
+	th.Vf = vf[0]
+	th.Z1 = complex128(th.Vf / i1)
+	th.I3LG = th.Base.CurrentFromPU(i1.Mag())
+	th.XR3LG, th.XRANSI3LG = xr3, xrANSI3
+
+	// Bolted single line to ground fault, sequence networks in series.
+	i0, _, i2, xr1, xrANSI1, err := c.theveninFault(busHnd, AG, th.Base)
+	if err != nil {
+		return nil, fmt.Errorf("Thevenin: 1LG fault: %v", err)
+	}
+	if i0 == 0 {
+		// Ungrounded or isolated zero sequence network.
+		th.Z0 = cmplx.Inf()
+		th.Z2 = th.Z1
+		th.NoGroundPath = true
+	} else {
+		_, _, v2, err := c.GetSCVoltageSeq(busHnd)
+		if err != nil {
+			return nil, fmt.Errorf("Thevenin: %v", err)
+		}
+		th.Z2 = complex128(-th.Base.VoltagePhasorToPU(v2) / i2)
+		th.Z0 = complex128(th.Vf/i0) - th.Z1 - th.Z2
+	}
+	th.I1LG = th.Base.CurrentFromPU(3 * i0.Mag())
+	th.XR1LG, th.XRANSI1LG = xr1, xrANSI1
+	return &th, nil
+}
+
+// TheveninTable represents the short circuit data for interconnection requests at several buses.
+type TheveninTable []*Thevenin
+
+// TheveninTable returns the Thevenin equivalents at each of the provided buses, see Thevenin.
+func (c *Client) TheveninTable(busHnds ...int) (TheveninTable, error) {
+	table := make(TheveninTable, 0, len(busHnds))
+	for _, hnd := range busHnds {
+		th, err := c.Thevenin(hnd)
+		if err != nil {
+			return nil, fmt.Errorf("TheveninTable: %s: %v", c.printID(hnd), err)
+		}
+		table = append(table, th)
+	}
+	return table, nil
+}
+
+// theveninHeader is the interconnection table header row.
+var theveninHeader = []string{
+	"Bus", "kV",
+	"3LG (A)", "3LG (MVA)", "3LG X/R",
+	"1LG (A)", "1LG (MVA)", "1LG X/R",
+	"R1 (pu)", "X1 (pu)", "R2 (pu)", "X2 (pu)", "R0 (pu)", "X0 (pu)",
+	"R1 (ohm)", "X1 (ohm)", "R0 (ohm)", "X0 (ohm)",
+}
+
+// Rows returns the interconnection table rows, including the header, formatted for output. The zero sequence
+// impedance cells are "open" at buses without a ground path.
+func (t TheveninTable) Rows() [][]string {
+	f := func(v float64, prec int) string {
+		return strconv.FormatFloat(v, 'f', prec, 64)
+	}
+	rows := [][]string{theveninHeader}
+	for _, th := range t {
+		z1, z0 := th.Z1Ohms(), th.Z0Ohms()
+		r0, x0 := f(real(th.Z0), 5), f(imag(th.Z0), 5)
+		r0Ohm, x0Ohm := f(real(z0), 3), f(imag(z0), 3)
+		if th.NoGroundPath {
+			r0, x0, r0Ohm, x0Ohm = "open", "open", "open", "open"
+		}
+		rows = append(rows, []string{
+			th.Name, f(th.KV, 2),
+			f(th.I3LG, 0), f(th.MVA3LG(), 1), f(th.XR3LG, 2),
+			f(th.I1LG, 0), f(th.MVA1LG(), 1), f(th.XR1LG, 2),
+			f(real(th.Z1), 5), f(imag(th.Z1), 5), f(real(th.Z2), 5), f(imag(th.Z2), 5), r0, x0,
+			f(real(z1), 3), f(imag(z1), 3), r0Ohm, x0Ohm,
+		})
+	}
+	return rows
+}
+
+// WriteCSV writes the interconnection table in CSV format.
+func (t TheveninTable) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(t.Rows()); err != nil {
+		return fmt.Errorf("WriteCSV: %v", err)
+	}
+	return nil
+}
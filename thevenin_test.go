@@ -0,0 +1,108 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math"
+	"math/cmplx"
+	"strings"
+	"testing"
+)
+
+func TestThevenin(t *testing.T) {
+	th := &Thevenin{
+		Name: "NEVADA",
+		KV:   132,
+		Base: Base{MVA: 100, KV: 132},
+		Z1:   complex(0.01, 0.1),
+		Z2:   complex(0.01, 0.1),
+		Z0:   complex(0.03, 0.3),
+		I3LG: 4373.89,
+	}
+	if got := th.XR1(); !almostEqual(got, 10) {
+		t.Errorf("expected X/R 10, got %v", got)
+	}
+	if got := th.Z1Ohms(); cmplx.Abs(got-complex(1.7424, 17.424)) > 1e-9 {
+		t.Errorf("expected Z1 1.7424+j17.424 ohms, got %v", got)
+	}
+	if got := th.MVA3LG(); math.Abs(got-1000) > 0.1 {
+		t.Errorf("expected 1000 MVA, got %v", got)
+	}
+	if got := xr(complex(0, 1)); !math.IsInf(got, 1) {
+		t.Errorf("expected infinite X/R, got %v", got)
+	}
+}
+
+func TestTheveninTable_WriteCSV(t *testing.T) {
+	table := TheveninTable{
+		{Name: "NEVADA", KV: 132, Base: Base{MVA: 100, KV: 132}, Z1: complex(0.01, 0.1), Z0: complex(0.03, 0.3)},
+		{Name: "OHIO", KV: 132, Base: Base{MVA: 100, KV: 132}, Z1: complex(0.02, 0.2), Z0: complex(0.06, 0.6)},
+	}
+	var buf bytes.Buffer
+	if err := table.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || len(rows[0]) != len(theveninHeader) {
+		t.Fatalf("expected header and 2 rows, got %v", rows)
+	}
+	if rows[2][0] != "OHIO" || rows[2][9] != "0.20000" {
+		t.Errorf("unexpected row %v", rows[2])
+	}
+}
+
+func TestTheveninTable_NoGroundPath(t *testing.T) {
+	th := &Thevenin{Name: "DELTA", KV: 13.8, Base: Base{MVA: 100, KV: 13.8}, Z1: complex(0.01, 0.1), Z2: complex(0.01, 0.1), Z0: cmplx.Inf(), NoGroundPath: true}
+	if got := th.XR0(); got != 0 {
+		t.Errorf("expected zero X/R, got %v", got)
+	}
+	rows := TheveninTable{th}.Rows()
+	for i, cell := range rows[1] {
+		if strings.Contains(cell, "Inf") || strings.Contains(cell, "NaN") {
+			t.Errorf("%s: unexpected cell %q", theveninHeader[i], cell)
+		}
+	}
+	for _, i := range []int{12, 13, 16, 17} {
+		if rows[1][i] != "open" {
+			t.Errorf("%s: expected open, got %q", theveninHeader[i], rows[1][i])
+		}
+	}
+}
+
+func TestClient_Thevenin(t *testing.T) {
+	c := NewClient()
+	defer c.Release()
+	if err := c.LoadDataFile(testCase); err != nil {
+		t.Fatal(err)
+	}
+	busHnd, err := c.FindBusByName("NEVADA", 132)
+	if err != nil {
+		t.Fatal(err)
+	}
+	th, err := c.Thevenin(busHnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if real(th.Z1) <= 0 || imag(th.Z1) <= 0 || th.I3LG <= 0 || th.I1LG <= 0 {
+		t.Errorf("unexpected equivalent %v", th)
+	}
+
+	// Reconstruct the 3LG fault current from the equivalent.
+	if i := th.Base.CurrentFromPU(cmplx.Abs(complex128(th.Vf) / th.Z1)); math.Abs(i-th.I3LG) > 1e-3*th.I3LG {
+		t.Errorf("expected 3LG current %v, got %v", th.I3LG, i)
+	}
+	table, err := c.TheveninTable(busHnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(table) != 1 {
+		t.Errorf("expected 1 row, got %d", len(table))
+	}
+}
@@ -0,0 +1,304 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrTxDone is returned by any operation performed on a transaction that has already been
+// committed or rolled back.
+var ErrTxDone = errors.New("transaction has already been committed or rolled back")
+
+// ChangeKind represents the kind of object modification recorded in a transaction change log.
+type ChangeKind int
+
+// Change kinds.
+const (
+	ChangeData ChangeKind = iota // Parameter data, see SetData.
+	ChangeTags                   // Object tags, see TagsSet.
+	ChangeMemo                   // Object memo field, see MemoSet.
+	ChangeUDF                    // User defined field, see SetUDF.
+)
+
+// String implements the stringer interface for the ChangeKind type.
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeData:
+		return "data"
+	case ChangeTags:
+		return "tags"
+	case ChangeMemo:
+		return "memo"
+	case ChangeUDF:
+		return "udf"
+	}
+	return fmt.Sprintf("ChangeKind(%d)", int(k))
+}
+
+// Change represents a single modification recorded by a transaction. Before holds the value prior
+// to the modification as returned by GetData, TagsGet, MemoGet or GetUDF, After holds the new value.
+type Change struct {
+	Hnd    int
+	Kind   ChangeKind
+	Token  int    // Parameter token, ChangeData only.
+	Field  string // User defined field name, ChangeUDF only.
+	Before interface{}
+	After  interface{}
+}
+
+// String implements the stringer interface for the Change type.
+func (ch Change) String() string {
+	var key string
+	switch ch.Kind {
+	case ChangeData:
+		key = fmt.Sprintf("token %d", ch.Token)
+	case ChangeUDF:
+		key = fmt.Sprintf("udf %q", ch.Field)
+	default:
+		key = ch.Kind.String()
+	}
+	return fmt.Sprintf("hnd %d %s: %v -> %v", ch.Hnd, key, ch.Before, ch.After)
+}
+
+// Tx represents an edit transaction. Modifications made through a Tx are recorded together with the
+// prior values, and are only applied to the case when Commit is called. Obtain using Client.Begin.
+//
+// A Tx is not safe for concurrent use, and the case should not be modified outside of the transaction
+// while it is open.
+type Tx struct {
+	c         *Client
+	changes   []Change
+	applied   int // number of changes applied to the case.
+	committed bool
+	done      bool
+}
+
+// Begin starts a new edit transaction.
+func (c *Client) Begin() *Tx {
+	return &Tx{c: c}
+}
+
+// Changes returns a copy of the change log recorded by the transaction, in the order the changes were made.
+func (tx *Tx) Changes() []Change {
+	changes := make([]Change, len(tx.changes))
+	copy(changes, tx.changes)
+	return changes
+}
+
+// Summary returns a human readable change log, one change per line.
+func (tx *Tx) Summary() string {
+	var sb strings.Builder
+	for _, ch := range tx.changes {
+		sb.WriteString(ch.String())
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// before returns the pending value for the object key if previously modified within the transaction.
+func (tx *Tx) before(hnd int, kind ChangeKind, token int, field string) (interface{}, bool) {
+	for i := len(tx.changes) - 1; i >= 0; i-- {
+		ch := tx.changes[i]
+		if ch.Hnd == hnd && ch.Kind == kind && ch.Token == token && ch.Field == field {
+			return ch.After, true
+		}
+	}
+	return nil, false
+}
+
+// SetData records a parameter change for the provided equipment handle and token. The prior value is
+// retrieved using GetData. Only string, float64, and int data is supported, see Client.SetData.
+func (tx *Tx) SetData(hnd, token int, data interface{}) error {
+	if tx.done {
+		return ErrTxDone
+	}
+	before, ok := tx.before(hnd, ChangeData, token, "")
+	if !ok {
+		var err error
+		before, err = tx.c.getData(hnd, token)
+		if err != nil {
+			return fmt.Errorf("SetData: could not get prior value for token %d: %v", token, err)
+		}
+	}
+	if !sameDataType(before, data) {
+		return fmt.Errorf("SetData: incorrect data type provided for token %d: %T", token, data)
+	}
+	tx.changes = append(tx.changes, Change{Hnd: hnd, Kind: ChangeData, Token: token, Before: before, After: data})
+	return nil
+}
+
+// sameDataType reports whether a and b are of the same supported SetData type.
+func sameDataType(a, b interface{}) bool {
+	switch a.(type) {
+	case string:
+		_, ok := b.(string)
+		return ok
+	case float64:
+		_, ok := b.(float64)
+		return ok
+	case int:
+		_, ok := b.(int)
+		return ok
+	}
+	return false
+}
+
+// TagsSet records a replacement of the object tags with the provided tags.
+func (tx *Tx) TagsSet(hnd int, tags ...string) error {
+	if tx.done {
+		return ErrTxDone
+	}
+	before, ok := tx.before(hnd, ChangeTags, 0, "")
+	if !ok {
+		var err error
+		before, err = tx.c.TagsGet(hnd)
+		if err != nil {
+			return err
+		}
+	}
+	tx.changes = append(tx.changes, Change{Hnd: hnd, Kind: ChangeTags, Before: before, After: tags})
+	return nil
+}
+
+// MemoSet records a replacement of the object memo field.
+func (tx *Tx) MemoSet(hnd int, memo string) error {
+	if tx.done {
+		return ErrTxDone
+	}
+	before, ok := tx.before(hnd, ChangeMemo, 0, "")
+	if !ok {
+		var err error
+		before, err = tx.c.MemoGet(hnd)
+		if err != nil {
+			return err
+		}
+	}
+	tx.changes = append(tx.changes, Change{Hnd: hnd, Kind: ChangeMemo, Before: before, After: memo})
+	return nil
+}
+
+// SetUDF records a change of the user defined field with the provided field name and value.
+func (tx *Tx) SetUDF(hnd int, field, value string) error {
+	if tx.done {
+		return ErrTxDone
+	}
+	before, ok := tx.before(hnd, ChangeUDF, 0, field)
+	if !ok {
+		var err error
+		before, err = tx.c.GetUDF(hnd, field)
+		if err != nil {
+			return err
+		}
+	}
+	tx.changes = append(tx.changes, Change{Hnd: hnd, Kind: ChangeUDF, Field: field, Before: before, After: value})
+	return nil
+}
+
+// Commit applies all recorded changes to the case, posting parameter data for each modified equipment.
+// If any change fails to apply, the changes already applied are restored to their original values and
+// the error is returned. A committed transaction may still be undone using Rollback.
+func (tx *Tx) Commit() error {
+	if tx.done || tx.committed {
+		return ErrTxDone
+	}
+	if err := tx.apply(); err != nil {
+		tx.done = true
+		if rbErr := tx.restore(); rbErr != nil {
+			return fmt.Errorf("Commit: %v; rollback failed: %v", err, rbErr)
+		}
+		return fmt.Errorf("Commit: %v", err)
+	}
+	tx.committed = true
+	return nil
+}
+
+// apply applies the recorded changes in order, PostData is called once per equipment handle after all
+// parameter data has been set.
+func (tx *Tx) apply() error {
+	var posts []int
+	for i, ch := range tx.changes {
+		if err := tx.c.applyChange(ch.Hnd, ch.Kind, ch.Token, ch.Field, ch.After); err != nil {
+			return err
+		}
+		tx.applied = i + 1
+		if ch.Kind == ChangeData && !containsInt(posts, ch.Hnd) {
+			posts = append(posts, ch.Hnd)
+		}
+	}
+	for _, hnd := range posts {
+		if err := tx.c.PostData(hnd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rollback discards the transaction. If the transaction has been committed, the original values recorded
+// prior to each change are restored in reverse order.
+func (tx *Tx) Rollback() error {
+	if tx.done {
+		return ErrTxDone
+	}
+	tx.done = true
+	if err := tx.restore(); err != nil {
+		return fmt.Errorf("Rollback: %v", err)
+	}
+	return nil
+}
+
+// restore restores the prior values of all applied changes in reverse order. Parameter data is posted for
+// each equipment handle once all values have been restored. Restoring continues past errors, the first
+// error encountered is returned.
+func (tx *Tx) restore() error {
+	var firstErr error
+	var posts []int
+	for i := tx.applied - 1; i >= 0; i-- {
+		ch := tx.changes[i]
+		if err := tx.c.applyChange(ch.Hnd, ch.Kind, ch.Token, ch.Field, ch.Before); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		if ch.Kind == ChangeData && !containsInt(posts, ch.Hnd) {
+			posts = append(posts, ch.Hnd)
+		}
+	}
+	for _, hnd := range posts {
+		if err := tx.c.PostData(hnd); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	tx.applied = 0
+	return firstErr
+}
+
+// applyChange sets the provided value for the object key. PostData is not called for parameter data.
+func (c *Client) applyChange(hnd int, kind ChangeKind, token int, field string, value interface{}) error {
+	switch kind {
+	case ChangeData:
+		return c.SetData(hnd, token, value)
+	case ChangeTags:
+		tags, _ := value.([]string)
+		return c.TagsSet(hnd, tags...)
+	case ChangeMemo:
+		memo, _ := value.(string)
+		return c.MemoSet(hnd, memo)
+	case ChangeUDF:
+		s, _ := value.(string)
+		return c.SetUDF(hnd, field, s)
+	}
+	return fmt.Errorf("applyChange: unsupported change kind %v", kind)
+}
+
+// containsInt reports whether v is within s.
+func containsInt(s []int, v int) bool {
+	for _, i := range s {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
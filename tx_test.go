@@ -0,0 +1,109 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"testing"
+)
+
+func TestClient_Begin(t *testing.T) {
+	c := NewClient()
+	defer c.Release()
+	if err := c.LoadDataFile(testCase); err != nil {
+		t.Fatal(err)
+	}
+	busHnd, err := c.FindBusByName("TENNESSEE", 132)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var origName string
+	var origArea int
+	if err := c.GetData(busHnd, BUSsName, BUSnArea).Scan(&origName, &origArea); err != nil {
+		t.Fatal(err)
+	}
+	origMemo, err := c.MemoGet(busHnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Incorrect type", func(t *testing.T) {
+		tx := c.Begin()
+		if err := tx.SetData(busHnd, BUSsName, 10); err == nil {
+			t.Errorf("expected incorrect data type error, got nil")
+		}
+	})
+	t.Run("Commit", func(t *testing.T) {
+		tx := c.Begin()
+		if err := tx.SetData(busHnd, BUSsName, "TX TEST"); err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.SetData(busHnd, BUSnArea, 99); err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.MemoSet(busHnd, "tx memo"); err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.TagsSet(busHnd, "TX"); err != nil {
+			t.Fatal(err)
+		}
+
+		// Nothing should be applied prior to commit.
+		var name string
+		c.GetData(busHnd, BUSsName).Scan(&name)
+		if name != origName {
+			t.Errorf("expected %q prior to commit, got %q", origName, name)
+		}
+
+		if err := tx.Commit(); err != nil {
+			t.Fatal(err)
+		}
+		var area int
+		if err := c.GetData(busHnd, BUSsName, BUSnArea).Scan(&name, &area); err != nil {
+			t.Fatal(err)
+		}
+		if name != "TX TEST" || area != 99 {
+			t.Errorf("expected TX TEST area 99, got %s area %d", name, area)
+		}
+
+		changes := tx.Changes()
+		if len(changes) != 4 {
+			t.Fatalf("expected 4 changes, got %d", len(changes))
+		}
+		if changes[0].Before != origName || changes[0].After != "TX TEST" {
+			t.Errorf("unexpected change log entry %v", changes[0])
+		}
+		t.Log(tx.Summary())
+
+		// Rollback after commit restores the original values.
+		if err := tx.Rollback(); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.GetData(busHnd, BUSsName, BUSnArea).Scan(&name, &area); err != nil {
+			t.Fatal(err)
+		}
+		if name != origName || area != origArea {
+			t.Errorf("expected %s area %d, got %s area %d", origName, origArea, name, area)
+		}
+		if memo, _ := c.MemoGet(busHnd); memo != origMemo {
+			t.Errorf("expected memo %q, got %q", origMemo, memo)
+		}
+		if tags, _ := c.TagsGet(busHnd); len(tags) != 0 {
+			t.Errorf("expected no tags, got %v", tags)
+		}
+	})
+	t.Run("Done", func(t *testing.T) {
+		tx := c.Begin()
+		if err := tx.Rollback(); err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.SetData(busHnd, BUSnArea, 1); err != ErrTxDone {
+			t.Errorf("expected %v, got %v", ErrTxDone, err)
+		}
+		if err := tx.Commit(); err != ErrTxDone {
+			t.Errorf("expected %v, got %v", ErrTxDone, err)
+		}
+	})
+}
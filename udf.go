@@ -0,0 +1,295 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// User defined field size limits. Longer field names and values are truncated by OlxAPI.
+const (
+	UDFFieldMaxLen = 16
+	UDFValueMaxLen = 64
+)
+
+// UDFDateLayout is the layout used to store date values in user defined fields, see SetUDFDate.
+const UDFDateLayout = "2006-01-02"
+
+// udfDateLayouts are the layouts accepted when parsing user defined field dates.
+var udfDateLayouts = []string{UDFDateLayout, "2006/1/2", "1/2/2006", time.RFC3339}
+
+// UDF represents a user defined field name and value.
+type UDF struct {
+	Field string
+	Value string
+}
+
+// UDFLimitError is returned when a user defined field name or value exceeds the OlxAPI size limits.
+type UDFLimitError struct {
+	Field string
+	Value string
+	Limit int
+}
+
+// Error implements the error interface for the UDFLimitError type.
+func (e UDFLimitError) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("user defined field name %q exceeds %d characters", e.Field, e.Limit)
+	}
+	return fmt.Sprintf("user defined field %q value %q exceeds %d characters", e.Field, e.Value, e.Limit)
+}
+
+// ValidateUDF returns a UDFLimitError if the field name or value exceeds the OlxAPI size limits. Limits are in
+// characters, not bytes.
+func ValidateUDF(field, value string) error {
+	if utf8.RuneCountInString(field) > UDFFieldMaxLen {
+		return UDFLimitError{Field: field, Limit: UDFFieldMaxLen}
+	}
+	if utf8.RuneCountInString(value) > UDFValueMaxLen {
+		return UDFLimitError{Field: field, Value: value, Limit: UDFValueMaxLen}
+	}
+	return nil
+}
+
+// UDFs returns all user defined fields and values for the equipment with the provided handle, in index order.
+// The fields are discovered using GetUDFByIndex until failure.
+func (c *Client) UDFs(hnd int) ([]UDF, error) {
+	if _, err := c.EquipmentType(hnd); err != nil {
+		return nil, err
+	}
+	var udfs []UDF
+	for i := 0; ; i++ {
+		field, value, err := c.GetUDFByIndex(hnd, i)
+		if err != nil {
+			break
+		}
+		udfs = append(udfs, UDF{Field: field, Value: value})
+	}
+	return udfs, nil
+}
+
+// UDFFields returns the user defined field names defined for the provided equipment type. The fields are
+// discovered from the first equipment of the type, an empty slice is returned if no equipment exists.
+func (c *Client) UDFFields(eqType int) ([]string, error) {
+	ei := c.NextEquipment(eqType)
+	if !ei.Next() {
+		return nil, nil
+	}
+	udfs, err := c.UDFs(ei.Hnd())
+	if err != nil {
+		return nil, err
+	}
+	fields := make([]string, len(udfs))
+	for i, u := range udfs {
+		fields[i] = u.Field
+	}
+	return fields, nil
+}
+
+// GetUDFInt returns the user defined field value parsed as an int.
+func (c *Client) GetUDFInt(hnd int, field string) (int, error) {
+	s, err := c.GetUDF(hnd, field)
+	if err != nil {
+		return 0, err
+	}
+	i, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("GetUDFInt: field %q: %v", field, err)
+	}
+	return i, nil
+}
+
+// GetUDFFloat returns the user defined field value parsed as a float64.
+func (c *Client) GetUDFFloat(hnd int, field string) (float64, error) {
+	s, err := c.GetUDF(hnd, field)
+	if err != nil {
+		return 0, err
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, fmt.Errorf("GetUDFFloat: field %q: %v", field, err)
+	}
+	return f, nil
+}
+
+// GetUDFDate returns the user defined field value parsed as a date. Accepted layouts are UDFDateLayout,
+// 2006/1/2, 1/2/2006 and RFC 3339.
+func (c *Client) GetUDFDate(hnd int, field string) (time.Time, error) {
+	s, err := c.GetUDF(hnd, field)
+	if err != nil {
+		return time.Time{}, err
+	}
+	t, err := parseUDFDate(strings.TrimSpace(s))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("GetUDFDate: field %q: %v", field, err)
+	}
+	return t, nil
+}
+
+// parseUDFDate parses s using the accepted user defined field date layouts.
+func parseUDFDate(s string) (time.Time, error) {
+	for _, layout := range udfDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unable to parse date %q", s)
+}
+
+// SetUDFInt sets the user defined field to the int value.
+func (c *Client) SetUDFInt(hnd int, field string, v int) error {
+	return c.SetUDF(hnd, field, strconv.Itoa(v))
+}
+
+// SetUDFFloat sets the user defined field to the float64 value.
+func (c *Client) SetUDFFloat(hnd int, field string, v float64) error {
+	return c.SetUDF(hnd, field, strconv.FormatFloat(v, 'g', -1, 64))
+}
+
+// SetUDFDate sets the user defined field to the date formatted using UDFDateLayout.
+func (c *Client) SetUDFDate(hnd int, field string, t time.Time) error {
+	return c.SetUDF(hnd, field, t.Format(UDFDateLayout))
+}
+
+// UDFKey represents the object identifier used as the key column for user defined field csv data.
+type UDFKey int
+
+// User defined field csv key types.
+const (
+	UDFKeyID   UDFKey = iota // 1LPF id string, see Print1LPF.
+	UDFKeyGUID               // Object GUID, see GetGUID.
+)
+
+// String returns the csv header for the key column.
+func (k UDFKey) String() string {
+	if k == UDFKeyGUID {
+		return "GUID"
+	}
+	return "ID"
+}
+
+// key returns the identifier for the provided handle.
+func (k UDFKey) key(c *Client, hnd int) (string, error) {
+	if k == UDFKeyGUID {
+		return c.GetGUID(hnd)
+	}
+	return c.Print1LPF(hnd)
+}
+
+// ExportUDFCSV writes the user defined fields for all equipment of the provided type to w in csv format.
+// The first column contains the object key, followed by one column per user defined field.
+func (c *Client) ExportUDFCSV(w io.Writer, eqType int, key UDFKey) error {
+	fields, err := c.UDFFields(eqType)
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(append([]string{key.String()}, fields...)); err != nil {
+		return err
+	}
+	for ei := c.NextEquipment(eqType); ei.Next(); {
+		hnd := ei.Hnd()
+		id, err := key.key(c, hnd)
+		if err != nil {
+			return fmt.Errorf("ExportUDFCSV: %v", err)
+		}
+		row := []string{id}
+		for _, f := range fields {
+			v, err := c.GetUDF(hnd, f)
+			if err != nil {
+				return fmt.Errorf("ExportUDFCSV: %s: %v", id, err)
+			}
+			row = append(row, v)
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ImportUDFCSV reads user defined field csv data from r, as written by ExportUDFCSV, and sets the values for
+// each row. The key type is determined by the first column header, ID or GUID. Objects are found using
+// Find1LPF for ID keys, or by searching equipment of the provided type for GUID keys.
+//
+// All values are validated against the size limits before any changes are made, and changes are applied
+// within a transaction which is rolled back on failure. Empty cells are skipped. Returns the number of
+// values set.
+func (c *Client) ImportUDFCSV(r io.Reader, eqType int) (int, error) {
+	cr := csv.NewReader(r)
+	records, err := cr.ReadAll()
+	if err != nil {
+		return 0, fmt.Errorf("ImportUDFCSV: %v", err)
+	}
+	if len(records) == 0 {
+		return 0, fmt.Errorf("ImportUDFCSV: missing header")
+	}
+	header := records[0]
+	var key UDFKey
+	switch strings.ToUpper(strings.TrimSpace(header[0])) {
+	case "ID":
+		key = UDFKeyID
+	case "GUID":
+		key = UDFKeyGUID
+	default:
+		return 0, fmt.Errorf("ImportUDFCSV: unknown key column %q, expected ID or GUID", header[0])
+	}
+	for _, f := range header[1:] {
+		if err := ValidateUDF(f, ""); err != nil {
+			return 0, fmt.Errorf("ImportUDFCSV: %v", err)
+		}
+	}
+
+	var guids map[string]int
+	if key == UDFKeyGUID {
+		guids = make(map[string]int)
+		for ei := c.NextEquipment(eqType); ei.Next(); {
+			if g, err := c.GetGUID(ei.Hnd()); err == nil {
+				guids[g] = ei.Hnd()
+			}
+		}
+	}
+
+	tx := c.Begin()
+	var n int
+	for i, row := range records[1:] {
+		line := i + 2
+		var hnd int
+		if key == UDFKeyGUID {
+			var ok bool
+			if hnd, ok = guids[row[0]]; !ok {
+				return 0, fmt.Errorf("ImportUDFCSV: line %d: GUID %s not found", line, row[0])
+			}
+		} else {
+			if hnd, err = c.Find1LPF(row[0]); err != nil {
+				return 0, fmt.Errorf("ImportUDFCSV: line %d: %v", line, err)
+			}
+		}
+		for j, v := range row[1:] {
+			if v == "" {
+				continue
+			}
+			field := header[j+1]
+			if err := ValidateUDF(field, v); err != nil {
+				return 0, fmt.Errorf("ImportUDFCSV: line %d: %v", line, err)
+			}
+			if err := tx.SetUDF(hnd, field, v); err != nil {
+				return 0, fmt.Errorf("ImportUDFCSV: line %d: %v", line, err)
+			}
+			n++
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("ImportUDFCSV: %v", err)
+	}
+	return n, nil
+}
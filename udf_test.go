@@ -0,0 +1,117 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateUDF(t *testing.T) {
+	tests := []struct {
+		name  string
+		field string
+		value string
+		ok    bool
+	}{
+		{name: "okay", field: "SUBID", value: "SUBA", ok: true},
+		{name: "field limit", field: strings.Repeat("F", UDFFieldMaxLen), value: "", ok: true},
+		{name: "field too long", field: strings.Repeat("F", UDFFieldMaxLen+1), value: "", ok: false},
+		{name: "value limit", field: "SUBID", value: strings.Repeat("V", UDFValueMaxLen), ok: true},
+		{name: "value too long", field: "SUBID", value: strings.Repeat("V", UDFValueMaxLen+1), ok: false},
+		{name: "multibyte field limit", field: strings.Repeat("É", UDFFieldMaxLen), value: "", ok: true},
+		{name: "multibyte field too long", field: strings.Repeat("É", UDFFieldMaxLen+1), value: "", ok: false},
+		{name: "multibyte value limit", field: "SUBID", value: strings.Repeat("é", UDFValueMaxLen), ok: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateUDF(tt.field, tt.value)
+			if (err == nil) != tt.ok {
+				t.Errorf("expected ok %v, got %v", tt.ok, err)
+			}
+			if err != nil {
+				if _, ok := err.(UDFLimitError); !ok {
+					t.Errorf("expected UDFLimitError, got %T", err)
+				}
+			}
+		})
+	}
+}
+
+func Test_parseUDFDate(t *testing.T) {
+	expected := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	for _, s := range []string{"2021-06-01", "2021/6/1", "6/1/2021"} {
+		got, err := parseUDFDate(s)
+		if err != nil {
+			t.Error(err)
+		}
+		if !got.Equal(expected) {
+			t.Errorf("%s: expected %v, got %v", s, expected, got)
+		}
+	}
+	if _, err := parseUDFDate("June"); err == nil {
+		t.Errorf("expected parse error, got nil")
+	}
+}
+
+func TestClient_UDFs(t *testing.T) {
+	c := NewClient()
+	defer c.Release()
+	if err := c.LoadDataFile(testCase); err != nil {
+		t.Fatal(err)
+	}
+	busHnd, err := c.FindBusByName("TENNESSEE", 132)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Run("Discovery", func(t *testing.T) {
+		fields, err := c.UDFFields(TCBus)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(fields) == 0 {
+			t.Errorf("expected bus user defined fields, got none")
+		}
+		t.Log(fields)
+	})
+	t.Run("Too long", func(t *testing.T) {
+		if err := c.SetUDF(busHnd, "SUBID", strings.Repeat("V", UDFValueMaxLen+1)); err == nil {
+			t.Errorf("expected UDFLimitError, got nil")
+		}
+	})
+	t.Run("Typed", func(t *testing.T) {
+		if err := c.SetUDFInt(busHnd, "SUBID", 42); err != nil {
+			t.Fatal(err)
+		}
+		got, err := c.GetUDFInt(busHnd, "SUBID")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != 42 {
+			t.Errorf("expected 42, got %d", got)
+		}
+	})
+	t.Run("CSV", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := c.ExportUDFCSV(&buf, TCBus, UDFKeyGUID); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.SetUDF(busHnd, "SUBID", "CHANGED"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.ImportUDFCSV(&buf, TCBus); err != nil {
+			t.Fatal(err)
+		}
+		got, err := c.GetUDF(busHnd, "SUBID")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != "42" {
+			t.Errorf("expected 42, got %q", got)
+		}
+	})
+}
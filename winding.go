@@ -0,0 +1,184 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// WindingType represents a transformer winding connection type.
+type WindingType int
+
+// Transformer winding connection types.
+const (
+	WindingWye WindingType = iota
+	WindingDelta
+	WindingZigzag
+)
+
+// String implements the stringer interface for the WindingType type.
+func (t WindingType) String() string {
+	switch t {
+	case WindingWye:
+		return "wye"
+	case WindingDelta:
+		return "delta"
+	case WindingZigzag:
+		return "zig-zag"
+	}
+	return fmt.Sprintf("WindingType(%d)", int(t))
+}
+
+// Winding represents a decoded transformer winding configuration.
+//
+// Oneliner winding configuration codes are decoded as follows:
+//
+//	G: wye, grounded
+//	W, Y: wye, ungrounded
+//	D: delta, lagging the wye reference by 30°
+//	E: delta, leading the wye reference by 30°
+//	Z: zig-zag, grounded
+type Winding struct {
+	Code     string
+	Type     WindingType
+	Grounded bool
+	Angle    float64 // Winding voltage angle relative to the wye reference, degrees.
+}
+
+// ParseWinding decodes the provided Oneliner winding configuration code. Returns an error if the code is unknown.
+func ParseWinding(code string) (Winding, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	w := Winding{Code: code}
+	switch code {
+	case "G":
+		w.Type, w.Grounded = WindingWye, true
+	case "W", "Y":
+		w.Type = WindingWye
+	case "D":
+		w.Type, w.Angle = WindingDelta, -30
+	case "E":
+		w.Type, w.Angle = WindingDelta, 30
+	case "Z":
+		w.Type, w.Grounded = WindingZigzag, true
+	default:
+		return w, fmt.Errorf("ParseWinding: unknown winding configuration %q", code)
+	}
+	return w, nil
+}
+
+// PassesZeroSeq reports whether zero sequence current can flow in the winding from the connected bus,
+// i.e. a grounded wye or zig-zag winding.
+func (w Winding) PassesZeroSeq() bool {
+	return w.Grounded && (w.Type == WindingWye || w.Type == WindingZigzag)
+}
+
+// Symbol returns the IEC 60076-1 winding symbol, upper case for high voltage windings and lower case otherwise.
+func (w Winding) Symbol(hv bool) string {
+	var s string
+	switch w.Type {
+	case WindingWye:
+		s = "Y"
+	case WindingDelta:
+		s = "D"
+	case WindingZigzag:
+		s = "Z"
+	}
+	if w.Grounded {
+		s += "N"
+	}
+	if !hv {
+		s = strings.ToLower(s)
+	}
+	return s
+}
+
+// String implements the stringer interface for the Winding type.
+func (w Winding) String() string {
+	if w.Grounded {
+		return fmt.Sprintf("%s grounded", w.Type)
+	}
+	return w.Type.String()
+}
+
+// PhaseShift returns the phase shift of the secondary winding voltage relative to the primary, in degrees
+// normalized to (-180, 180]. Positive values indicate the secondary leads the primary.
+func PhaseShift(primary, secondary Winding) float64 {
+	shift := math.Mod(secondary.Angle-primary.Angle, 360)
+	switch {
+	case shift > 180:
+		shift -= 360
+	case shift <= -180:
+		shift += 360
+	}
+	return shift
+}
+
+// ClockNumber returns the IEC vector group clock number for the provided phase shift in degrees, i.e. the
+// number of 30° steps the secondary lags the primary.
+func ClockNumber(shift float64) int {
+	n := int(math.Round(-shift/30)) % 12
+	if n < 0 {
+		n += 12
+	}
+	return n
+}
+
+// VectorGroup returns the IEC vector group designation for the primary (high voltage) and secondary windings,
+// e.g. Dyn1 or YNd11.
+func VectorGroup(primary, secondary Winding) string {
+	return fmt.Sprintf("%s%s%d", primary.Symbol(true), secondary.Symbol(false), ClockNumber(PhaseShift(primary, secondary)))
+}
+
+// ZeroSeqPath represents the zero sequence connectivity of a two winding transformer.
+type ZeroSeqPath int
+
+// Two winding transformer zero sequence connectivity.
+const (
+	ZeroSeqOpen           ZeroSeqPath = iota // Zero sequence current blocked on both sides.
+	ZeroSeqSeries                            // Zero sequence current passes through between buses.
+	ZeroSeqShuntPrimary                      // Primary side ground path, blocked between buses.
+	ZeroSeqShuntSecondary                    // Secondary side ground path, blocked between buses.
+	ZeroSeqShuntBoth                         // Ground path on both sides, blocked between buses.
+)
+
+// String implements the stringer interface for the ZeroSeqPath type.
+func (z ZeroSeqPath) String() string {
+	switch z {
+	case ZeroSeqOpen:
+		return "open"
+	case ZeroSeqSeries:
+		return "series"
+	case ZeroSeqShuntPrimary:
+		return "shunt primary"
+	case ZeroSeqShuntSecondary:
+		return "shunt secondary"
+	case ZeroSeqShuntBoth:
+		return "shunt both"
+	}
+	return fmt.Sprintf("ZeroSeqPath(%d)", int(z))
+}
+
+// ZeroSeqConnection returns the zero sequence connectivity of a two winding transformer with the provided
+// windings. Grounded wye windings on both sides pass zero sequence current between buses. A grounded winding
+// opposite a delta winding provides a ground path on its own side only, as does a zig-zag winding.
+func ZeroSeqConnection(primary, secondary Winding) ZeroSeqPath {
+	pWye := primary.PassesZeroSeq() && primary.Type == WindingWye
+	sWye := secondary.PassesZeroSeq() && secondary.Type == WindingWye
+	pShunt := primary.PassesZeroSeq() && (primary.Type == WindingZigzag || secondary.Type == WindingDelta)
+	sShunt := secondary.PassesZeroSeq() && (secondary.Type == WindingZigzag || primary.Type == WindingDelta)
+	switch {
+	case pWye && sWye:
+		return ZeroSeqSeries
+	case pShunt && sShunt:
+		return ZeroSeqShuntBoth
+	case pShunt:
+		return ZeroSeqShuntPrimary
+	case sShunt:
+		return ZeroSeqShuntSecondary
+	}
+	return ZeroSeqOpen
+}
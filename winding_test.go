@@ -0,0 +1,93 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"testing"
+)
+
+func TestParseWinding(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected string
+		zeroSeq  bool
+		err      bool
+	}{
+		{code: "G", expected: "wye grounded", zeroSeq: true},
+		{code: "w", expected: "wye"},
+		{code: "D", expected: "delta"},
+		{code: "E", expected: "delta"},
+		{code: "Z", expected: "zig-zag grounded", zeroSeq: true},
+		{code: "Q", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			w, err := ParseWinding(tt.code)
+			if (err != nil) != tt.err {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if got := w.String(); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+			if got := w.PassesZeroSeq(); got != tt.zeroSeq {
+				t.Errorf("expected zero sequence %v, got %v", tt.zeroSeq, got)
+			}
+		})
+	}
+}
+
+func TestVectorGroup(t *testing.T) {
+	tests := []struct {
+		p, s    string
+		group   string
+		shift   float64
+		zeroSeq ZeroSeqPath
+	}{
+		{p: "G", s: "G", group: "YNyn0", shift: 0, zeroSeq: ZeroSeqSeries},
+		{p: "G", s: "D", group: "YNd1", shift: -30, zeroSeq: ZeroSeqShuntPrimary},
+		{p: "G", s: "E", group: "YNd11", shift: 30, zeroSeq: ZeroSeqShuntPrimary},
+		{p: "D", s: "G", group: "Dyn11", shift: 30, zeroSeq: ZeroSeqShuntSecondary},
+		{p: "E", s: "G", group: "Dyn1", shift: -30, zeroSeq: ZeroSeqShuntSecondary},
+		{p: "D", s: "D", group: "Dd0", shift: 0, zeroSeq: ZeroSeqOpen},
+		{p: "D", s: "E", group: "Dd10", shift: 60, zeroSeq: ZeroSeqOpen},
+		{p: "G", s: "W", group: "YNy0", shift: 0, zeroSeq: ZeroSeqOpen},
+		{p: "W", s: "D", group: "Yd1", shift: -30, zeroSeq: ZeroSeqOpen},
+		{p: "G", s: "Z", group: "YNzn0", shift: 0, zeroSeq: ZeroSeqShuntSecondary},
+		{p: "Z", s: "Z", group: "ZNzn0", shift: 0, zeroSeq: ZeroSeqShuntBoth},
+	}
+	for _, tt := range tests {
+		t.Run(tt.p+tt.s, func(t *testing.T) {
+			p, err := ParseWinding(tt.p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s, err := ParseWinding(tt.s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := VectorGroup(p, s); got != tt.group {
+				t.Errorf("expected %s, got %s", tt.group, got)
+			}
+			if got := PhaseShift(p, s); got != tt.shift {
+				t.Errorf("expected shift %v, got %v", tt.shift, got)
+			}
+			if got := ZeroSeqConnection(p, s); got != tt.zeroSeq {
+				t.Errorf("expected %v, got %v", tt.zeroSeq, got)
+			}
+		})
+	}
+}
+
+func TestClockNumber(t *testing.T) {
+	tests := map[float64]int{0: 0, -30: 1, 30: 11, 180: 6, -180: 6, -150: 5, 150: 7}
+	for shift, expected := range tests {
+		if got := ClockNumber(shift); got != expected {
+			t.Errorf("shift %v: expected %d, got %d", shift, expected, got)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Xfmr represents a two winding transformer data object.
+type Xfmr struct {
+	Hnd          int
+	Bus1         *Bus // Primary bus.
+	Bus2         *Bus // Secondary bus.
+	CktID        string
+	Name         string
+	InService    int
+	Auto         int
+	RelayGrp1Hnd int
+	RelayGrp2Hnd int
+
+	// Winding configuration codes, see ParseWinding.
+	CfgP, CfgS string
+	CfgST      string // Secondary winding configuration in the zero sequence test.
+
+	// Decoded winding configurations. SecondaryTest is the zero value where CfgST is blank.
+	Primary, Secondary Winding
+	SecondaryTest      Winding
+
+	// Ratings and taps.
+	MVA            float64
+	BaseMVA        float64
+	PriTap, SecTap float64
+
+	// Transformer impedances.
+	R, X, B    float64
+	R0, X0, B0 float64
+
+	// Grounding impedances, primary, secondary and common neutral.
+	RG1, XG1 float64
+	RG2, XG2 float64
+	RGN, XGN float64
+}
+
+func (x *Xfmr) String() string {
+	return fmt.Sprintf("%s-%s ckt:%s", x.Bus1, x.Bus2, x.CktID)
+}
+
+// VectorGroup returns the IEC vector group designation, e.g. Dyn1. See VectorGroup.
+func (x *Xfmr) VectorGroup() string {
+	return VectorGroup(x.Primary, x.Secondary)
+}
+
+// PhaseShift returns the phase shift of the secondary relative to the primary in degrees. See PhaseShift.
+func (x *Xfmr) PhaseShift() float64 {
+	return PhaseShift(x.Primary, x.Secondary)
+}
+
+// ZeroSeq returns the zero sequence connectivity of the transformer. The windings of an autotransformer share
+// the series winding and neutral, so wye windings pass zero sequence current between buses whether or not the
+// neutral is grounded. See ZeroSeqConnection for other transformers.
+func (x *Xfmr) ZeroSeq() ZeroSeqPath {
+	if x.Auto != 0 && x.Primary.Type == WindingWye && x.Secondary.Type == WindingWye {
+		return ZeroSeqSeries
+	}
+	return ZeroSeqConnection(x.Primary, x.Secondary)
+}
+
+// NeutralZ returns the common neutral grounding impedance.
+func (x *Xfmr) NeutralZ() complex128 {
+	return complex(x.RGN, x.XGN)
+}
+
+// GetXfmr loads the transformer data at the provided handle into a new Xfmr object. Returns error
+// if the handle provided does not point to an equipment type TCXFMR, or if the winding configurations
+// cannot be decoded.
+func (c *Client) GetXfmr(hnd int) (*Xfmr, error) {
+	if eqType, _ := c.EquipmentType(hnd); eqType != TCXFMR {
+		return nil, fmt.Errorf("GetXfmr: equipment type must be TCXFMR")
+	}
+	var x = Xfmr{Hnd: hnd}
+	data := c.GetData(hnd,
+		XRnBus1Hnd,
+		XRnBus2Hnd,
+		XRsID,
+		XRsName,
+		XRnInService,
+		XRnAuto,
+		XRsCfgP,
+		XRsCfgS,
+		XRsCfgST,
+		XRdMVA,
+		XRdBaseMVA,
+		XRdPriTap,
+		XRdSecTap,
+		XRdR, XRdX, XRdB,
+		XRdR0, XRdX0, XRdB0,
+		XRdRG1, XRdXG1,
+		XRdRG2, XRdXG2,
+		XRdRGN, XRdXGN,
+	)
+
+	var bus1Hnd, bus2Hnd int
+	if err := data.Scan(
+		&bus1Hnd,
+		&bus2Hnd,
+		&x.CktID,
+		&x.Name,
+		&x.InService,
+		&x.Auto,
+		&x.CfgP,
+		&x.CfgS,
+		&x.CfgST,
+		&x.MVA,
+		&x.BaseMVA,
+		&x.PriTap,
+		&x.SecTap,
+		&x.R, &x.X, &x.B,
+		&x.R0, &x.X0, &x.B0,
+		&x.RG1, &x.XG1,
+		&x.RG2, &x.XG2,
+		&x.RGN, &x.XGN,
+	); err != nil {
+		return nil, fmt.Errorf("GetXfmr: could not scan transformer data %v", err)
+	}
+
+	var err error
+	if x.Primary, err = ParseWinding(x.CfgP); err != nil {
+		return nil, fmt.Errorf("GetXfmr: primary: %v", err)
+	}
+	if x.Secondary, err = ParseWinding(x.CfgS); err != nil {
+		return nil, fmt.Errorf("GetXfmr: secondary: %v", err)
+	}
+	if strings.TrimSpace(x.CfgST) != "" {
+		if x.SecondaryTest, err = ParseWinding(x.CfgST); err != nil {
+			return nil, fmt.Errorf("GetXfmr: secondary test: %v", err)
+		}
+	}
+
+	// Ignoring error on relaygroup lookup. OlxAPI throws error if relay groups not present, we can default to zero value.
+	c.GetData(hnd, XRnRlyGr1Hnd, XRnRlyGr2Hnd).Scan(&x.RelayGrp1Hnd, &x.RelayGrp2Hnd)
+
+	// Get bus1 data.
+	if b, _ := c.getBus(bus1Hnd); b != nil {
+		x.Bus1 = b
+	}
+
+	// Get bus2 data.
+	if b, _ := c.getBus(bus2Hnd); b != nil {
+		x.Bus2 = b
+	}
+
+	return &x, nil
+}
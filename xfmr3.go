@@ -0,0 +1,237 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import "fmt"
+
+// Xfmr3 represents a three winding transformer data object. Pairwise impedances are in per unit on BaseMVA.
+type Xfmr3 struct {
+	Hnd          int
+	Bus1         *Bus // Primary bus.
+	Bus2         *Bus // Secondary bus.
+	Bus3         *Bus // Tertiary bus.
+	CktID        string
+	Name         string
+	InService    int
+	Auto         int
+	FictBusNo    int
+	RelayGrp1Hnd int
+	RelayGrp2Hnd int
+	RelayGrp3Hnd int
+
+	// Winding configuration codes, see ParseWinding.
+	CfgP, CfgS, CfgT string
+	CfgST, CfgTT     string // Secondary and tertiary winding configurations in the zero sequence test.
+
+	// Decoded winding configurations.
+	Primary, Secondary, Tertiary Winding
+
+	// Ratings.
+	MVA1, MVA2, MVA3 float64
+	BaseMVA          float64
+
+	// Taps.
+	PriTap, SecTap, TerTap float64
+	LTCCenterTap           float64
+	MinTap, MaxTap         float64
+	LTCStep                float64
+
+	// Pairwise impedances, primary-secondary, primary-tertiary and secondary-tertiary.
+	Rps, Xps, R0ps, X0ps float64
+	Rpt, Xpt, R0pt, X0pt float64
+	Rst, Xst, R0st, X0st float64
+	B, B0                float64
+
+	// Grounding impedances, primary, secondary, tertiary and common neutral.
+	RG1, XG1 float64
+	RG2, XG2 float64
+	RG3, XG3 float64
+	RGN, XGN float64
+}
+
+func (x *Xfmr3) String() string {
+	return fmt.Sprintf("%s-%s-%s ckt:%s", x.Bus1, x.Bus2, x.Bus3, x.CktID)
+}
+
+// StarEquivalent converts pairwise winding impedances into the star (T) equivalent impedances of the primary,
+// secondary and tertiary branches. All impedances must be on a common MVA base.
+func StarEquivalent(zps, zpt, zst complex128) (zp, zs, zt complex128) {
+	zp = (zps + zpt - zst) / 2
+	zs = (zps + zst - zpt) / 2
+	zt = (zpt + zst - zps) / 2
+	return zp, zs, zt
+}
+
+// star returns the star equivalent of the pairwise impedances on the transformer BaseMVA, converted to the provided
+// MVA base. The transformer BaseMVA is used if baseMVA is zero.
+func (x *Xfmr3) star(zps, zpt, zst complex128, baseMVA float64) (zp, zs, zt complex128) {
+	zp, zs, zt = StarEquivalent(zps, zpt, zst)
+	if baseMVA == 0 || x.BaseMVA == 0 {
+		return zp, zs, zt
+	}
+	from, to := Base{MVA: x.BaseMVA, KV: 1}, Base{MVA: baseMVA, KV: 1}
+	return ChangeBase(zp, from, to), ChangeBase(zs, from, to), ChangeBase(zt, from, to)
+}
+
+// Star returns the positive sequence star equivalent impedances in per unit on the provided MVA base.
+// The transformer BaseMVA is used if baseMVA is zero.
+func (x *Xfmr3) Star(baseMVA float64) (zp, zs, zt complex128) {
+	return x.star(complex(x.Rps, x.Xps), complex(x.Rpt, x.Xpt), complex(x.Rst, x.Xst), baseMVA)
+}
+
+// Star0 returns the zero sequence star equivalent impedances in per unit on the provided MVA base.
+// The transformer BaseMVA is used if baseMVA is zero.
+func (x *Xfmr3) Star0(baseMVA float64) (zp, zs, zt complex128) {
+	return x.star(complex(x.R0ps, x.X0ps), complex(x.R0pt, x.X0pt), complex(x.R0st, x.X0st), baseMVA)
+}
+
+// StarZeroSeq represents the zero sequence connection of a winding branch within the star equivalent circuit.
+type StarZeroSeq int
+
+// Star equivalent winding branch zero sequence connections.
+const (
+	StarZeroSeqOpen      StarZeroSeq = iota // Branch open at the bus side, e.g. ungrounded wye.
+	StarZeroSeqBus                          // Branch connected to the bus, e.g. grounded wye.
+	StarZeroSeqGround                       // Branch connected to ground, bus side open, e.g. delta.
+	StarZeroSeqBusGround                    // Bus side grounded through the winding, star side open, e.g. zig-zag.
+)
+
+// String implements the stringer interface for the StarZeroSeq type.
+func (z StarZeroSeq) String() string {
+	switch z {
+	case StarZeroSeqOpen:
+		return "open"
+	case StarZeroSeqBus:
+		return "bus"
+	case StarZeroSeqGround:
+		return "ground"
+	case StarZeroSeqBusGround:
+		return "bus ground"
+	}
+	return fmt.Sprintf("StarZeroSeq(%d)", int(z))
+}
+
+// StarZeroSeq returns the zero sequence connection of the winding branch within a star equivalent circuit.
+func (w Winding) StarZeroSeq() StarZeroSeq {
+	switch {
+	case w.Type == WindingDelta:
+		return StarZeroSeqGround
+	case w.Type == WindingZigzag && w.Grounded:
+		return StarZeroSeqBusGround
+	case w.Type == WindingWye && w.Grounded:
+		return StarZeroSeqBus
+	}
+	return StarZeroSeqOpen
+}
+
+// ZeroSeq returns the zero sequence connections of the primary, secondary and tertiary star equivalent branches.
+// The primary and secondary windings of an autotransformer share the series winding and neutral, so wye windings
+// connect to the bus whether or not the neutral is grounded. The tertiary connection provides the ground path at
+// the star point, e.g. a delta tertiary.
+func (x *Xfmr3) ZeroSeq() (p, s, t StarZeroSeq) {
+	p, s, t = x.Primary.StarZeroSeq(), x.Secondary.StarZeroSeq(), x.Tertiary.StarZeroSeq()
+	if x.Auto != 0 && x.Primary.Type == WindingWye && x.Secondary.Type == WindingWye {
+		p, s = StarZeroSeqBus, StarZeroSeqBus
+	}
+	return p, s, t
+}
+
+// NeutralZ returns the common neutral grounding impedance.
+func (x *Xfmr3) NeutralZ() complex128 {
+	return complex(x.RGN, x.XGN)
+}
+
+// GetXfmr3 loads the three winding transformer data at the provided handle into a new Xfmr3 object. Returns
+// error if the handle provided does not point to an equipment type TCXFMR3, or if the winding configurations
+// cannot be decoded.
+func (c *Client) GetXfmr3(hnd int) (*Xfmr3, error) {
+	if eqType, _ := c.EquipmentType(hnd); eqType != TCXFMR3 {
+		return nil, fmt.Errorf("GetXfmr3: equipment type must be TCXFMR3")
+	}
+	var x = Xfmr3{Hnd: hnd}
+	data := c.GetData(hnd,
+		X3nBus1Hnd,
+		X3nBus2Hnd,
+		X3nBus3Hnd,
+		X3sID,
+		X3sName,
+		X3nInService,
+		X3nAuto,
+		X3nFictBusNo,
+		X3sCfgP, X3sCfgS, X3sCfgT,
+		X3sCfgST, X3sCfgTT,
+		X3dMVA1, X3dMVA2, X3dMVA3,
+		X3dBaseMVA,
+		X3dPriTap, X3dSecTap, X3dTerTap,
+		X3dLTCCenterTap,
+		X3dMinTap, X3dMaxTap,
+		X3dLTCstep,
+		X3dRps, X3dXps, X3dR0ps, X3dX0ps,
+		X3dRpt, X3dXpt, X3dR0pt, X3dX0pt,
+		X3dRst, X3dXst, X3dR0st, X3dX0st,
+		X3dB, X3dB0,
+		X3dRG1, X3dXG1,
+		X3dRG2, X3dXG2,
+		X3dRG3, X3dXG3,
+		X3dRGN, X3dXGN,
+	)
+
+	var bus1Hnd, bus2Hnd, bus3Hnd int
+	if err := data.Scan(
+		&bus1Hnd,
+		&bus2Hnd,
+		&bus3Hnd,
+		&x.CktID,
+		&x.Name,
+		&x.InService,
+		&x.Auto,
+		&x.FictBusNo,
+		&x.CfgP, &x.CfgS, &x.CfgT,
+		&x.CfgST, &x.CfgTT,
+		&x.MVA1, &x.MVA2, &x.MVA3,
+		&x.BaseMVA,
+		&x.PriTap, &x.SecTap, &x.TerTap,
+		&x.LTCCenterTap,
+		&x.MinTap, &x.MaxTap,
+		&x.LTCStep,
+		&x.Rps, &x.Xps, &x.R0ps, &x.X0ps,
+		&x.Rpt, &x.Xpt, &x.R0pt, &x.X0pt,
+		&x.Rst, &x.Xst, &x.R0st, &x.X0st,
+		&x.B, &x.B0,
+		&x.RG1, &x.XG1,
+		&x.RG2, &x.XG2,
+		&x.RG3, &x.XG3,
+		&x.RGN, &x.XGN,
+	); err != nil {
+		return nil, fmt.Errorf("GetXfmr3: could not scan transformer data %v", err)
+	}
+
+	var err error
+	if x.Primary, err = ParseWinding(x.CfgP); err != nil {
+		return nil, fmt.Errorf("GetXfmr3: primary: %v", err)
+	}
+	if x.Secondary, err = ParseWinding(x.CfgS); err != nil {
+		return nil, fmt.Errorf("GetXfmr3: secondary: %v", err)
+	}
+	if x.Tertiary, err = ParseWinding(x.CfgT); err != nil {
+		return nil, fmt.Errorf("GetXfmr3: tertiary: %v", err)
+	}
+
+	// Ignoring error on relaygroup lookup. OlxAPI throws error if relay groups not present, we can default to zero value.
+	c.GetData(hnd, X3nRlyGr1Hnd, X3nRlyGr2Hnd, X3nRlyGr3Hnd).Scan(&x.RelayGrp1Hnd, &x.RelayGrp2Hnd, &x.RelayGrp3Hnd)
+
+	// Get bus data.
+	if b, _ := c.getBus(bus1Hnd); b != nil {
+		x.Bus1 = b
+	}
+	if b, _ := c.getBus(bus2Hnd); b != nil {
+		x.Bus2 = b
+	}
+	if b, _ := c.getBus(bus3Hnd); b != nil {
+		x.Bus3 = b
+	}
+
+	return &x, nil
+}
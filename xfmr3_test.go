@@ -0,0 +1,68 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import (
+	"math/cmplx"
+	"testing"
+)
+
+func TestStarEquivalent(t *testing.T) {
+	zp, zs, zt := StarEquivalent(complex(0.01, 0.1), complex(0.02, 0.2), complex(0.015, 0.15))
+	for _, tt := range []struct {
+		got, expected complex128
+	}{
+		{zp, complex(0.0075, 0.075)},
+		{zs, complex(0.0025, 0.025)},
+		{zt, complex(0.0125, 0.125)},
+	} {
+		if cmplx.Abs(tt.got-tt.expected) > 1e-12 {
+			t.Errorf("expected %v, got %v", tt.expected, tt.got)
+		}
+	}
+}
+
+func TestXfmr3_Star(t *testing.T) {
+	x := Xfmr3{BaseMVA: 50, Xps: 0.1, Xpt: 0.2, Xst: 0.15}
+	zp, zs, zt := x.Star(100)
+	if cmplx.Abs(zp-complex(0, 0.15)) > 1e-12 || cmplx.Abs(zs-complex(0, 0.05)) > 1e-12 || cmplx.Abs(zt-complex(0, 0.25)) > 1e-12 {
+		t.Errorf("unexpected star impedances %v %v %v", zp, zs, zt)
+	}
+	if zp2, _, _ := x.Star(0); cmplx.Abs(zp2-complex(0, 0.075)) > 1e-12 {
+		t.Errorf("expected transformer base impedance, got %v", zp2)
+	}
+}
+
+func TestXfmr3_ZeroSeq(t *testing.T) {
+	tests := []struct {
+		p, s, tr string
+		auto     int
+		expected [3]StarZeroSeq
+	}{
+		{"G", "G", "D", 0, [3]StarZeroSeq{StarZeroSeqBus, StarZeroSeqBus, StarZeroSeqGround}},
+		{"G", "W", "E", 0, [3]StarZeroSeq{StarZeroSeqBus, StarZeroSeqOpen, StarZeroSeqGround}},
+		{"D", "G", "Z", 0, [3]StarZeroSeq{StarZeroSeqGround, StarZeroSeqBus, StarZeroSeqBusGround}},
+		{"W", "W", "D", 0, [3]StarZeroSeq{StarZeroSeqOpen, StarZeroSeqOpen, StarZeroSeqGround}},
+		{"W", "W", "D", 1, [3]StarZeroSeq{StarZeroSeqBus, StarZeroSeqBus, StarZeroSeqGround}},
+		{"G", "W", "D", 1, [3]StarZeroSeq{StarZeroSeqBus, StarZeroSeqBus, StarZeroSeqGround}},
+	}
+	for _, tt := range tests {
+		x := Xfmr3{Auto: tt.auto}
+		var err error
+		if x.Primary, err = ParseWinding(tt.p); err != nil {
+			t.Fatal(err)
+		}
+		if x.Secondary, err = ParseWinding(tt.s); err != nil {
+			t.Fatal(err)
+		}
+		if x.Tertiary, err = ParseWinding(tt.tr); err != nil {
+			t.Fatal(err)
+		}
+		p, s, tr := x.ZeroSeq()
+		if got := [3]StarZeroSeq{p, s, tr}; got != tt.expected {
+			t.Errorf("%s%s%s auto=%d: expected %v, got %v", tt.p, tt.s, tt.tr, tt.auto, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2021 readpe All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package goolx
+
+import "testing"
+
+func TestXfmr_ZeroSeq(t *testing.T) {
+	tests := []struct {
+		p, s     string
+		auto     int
+		expected ZeroSeqPath
+	}{
+		{"G", "G", 0, ZeroSeqSeries},
+		{"G", "W", 0, ZeroSeqOpen},
+		{"W", "W", 0, ZeroSeqOpen},
+		{"G", "D", 0, ZeroSeqShuntPrimary},
+		{"G", "W", 1, ZeroSeqSeries},
+		{"W", "W", 1, ZeroSeqSeries},
+		{"G", "G", 1, ZeroSeqSeries},
+	}
+	for _, tt := range tests {
+		x := Xfmr{Auto: tt.auto}
+		x.Primary, _ = ParseWinding(tt.p)
+		x.Secondary, _ = ParseWinding(tt.s)
+		if got := x.ZeroSeq(); got != tt.expected {
+			t.Errorf("%s%s auto=%d: expected %v, got %v", tt.p, tt.s, tt.auto, tt.expected, got)
+		}
+	}
+}